@@ -2,39 +2,122 @@
 package ast
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"unicode"
 	"unicode/utf8"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/alexeyco/hanjie/errors"
 )
 
 // PuzzleSet the root structure of the document. Includes all puzzles.
 type PuzzleSet []Puzzle
 
+// Collection of puzzles with metadata. Revision is the version of the collection itself,
+// the "version" key of the document is the version of the document format.
+type Collection struct {
+	Name        string    `yaml:"name,omitempty" json:"name,omitempty"`
+	Author      *Author   `yaml:"author,omitempty" json:"author,omitempty"`
+	License     string    `yaml:"license,omitempty" json:"license,omitempty"`
+	Revision    string    `yaml:"revision,omitempty" json:"revision,omitempty"`
+	Description string    `yaml:"description,omitempty" json:"description,omitempty"`
+	Puzzles     PuzzleSet `yaml:"puzzles" json:"puzzles"`
+}
+
+// HasMetadata reports whether the collection has any metadata besides puzzles.
+func (c Collection) HasMetadata() bool {
+	return c.Name != "" || c.Author != nil || c.License != "" || c.Revision != "" || c.Description != ""
+}
+
 // Puzzle a puzzle in the set of puzzles.
 type Puzzle struct {
-	ID          string  `yaml:"id,omitempty"`
-	Source      string  `yaml:"source,omitempty"`
-	Author      *Author `yaml:"author,omitempty"`
-	Copyright   string  `yaml:"copyright,omitempty"`
-	Title       string  `yaml:"title"`
-	Description string  `yaml:"description,omitempty"`
-	Background  Char    `yaml:"background"`
-	Colors      Colors  `yaml:"colors"`
-	Clue        Clue    `yaml:"clue"`
-	Goal        *Goal   `yaml:"goal,flow,omitempty"`
+	ID          string  `yaml:"id,omitempty" json:"id,omitempty"`
+	Source      string  `yaml:"source,omitempty" json:"source,omitempty"`
+	Author      *Author `yaml:"author,omitempty" json:"author,omitempty"`
+	Copyright   string  `yaml:"copyright,omitempty" json:"copyright,omitempty"`
+	Title       string  `yaml:"title" json:"title"`
+	Description string  `yaml:"description,omitempty" json:"description,omitempty"`
+	Background  Char    `yaml:"background" json:"background"`
+	Colors      Colors  `yaml:"colors" json:"colors"`
+	Clue        Clue    `yaml:"clue" json:"clue"`
+	Goal        *Goal   `yaml:"goal,omitempty" json:"goal,omitempty"`
+	// Difficulty is the rating of the puzzle, see solver.Rate and solver.FillDifficulty.
+	Difficulty *Difficulty `yaml:"difficulty,omitempty" json:"difficulty,omitempty"`
+	// Extensions are vendor-specific fields, keys start with ExtensionPrefix, e.g. "x-rating".
+	Extensions map[string]interface{} `yaml:"-" json:"-"`
+}
+
+// Difficulty of the puzzle by the deduction techniques needed to solve it: the score, the label
+// and the number of deductions made by every technique.
+type Difficulty struct {
+	Score   int    `yaml:"score" json:"score"`
+	Label   string `yaml:"label" json:"label"`
+	Lines   int    `yaml:"lines" json:"lines"`
+	Probes  int    `yaml:"probes" json:"probes"`
+	Guesses int    `yaml:"guesses" json:"guesses"`
 }
 
 // Author of puzzle.
 type Author struct {
-	Name string `yaml:"name"`
-	ID   string `yaml:"id"`
+	Name string `yaml:"name" json:"name"`
+	ID   string `yaml:"id" json:"id"`
 }
 
 // Colors used in puzzle.
 type Colors map[Char]Color
 
+// Chars returns chars of the colors in ascending order.
+func (c Colors) Chars() []Char {
+	chars := make([]Char, 0, len(c))
+	for ch := range c {
+		chars = append(chars, ch)
+	}
+
+	sort.Slice(chars, func(i, j int) bool {
+		return chars[i] < chars[j]
+	})
+
+	return chars
+}
+
+// MarshalYAML encodes colors as a mapping sorted by chars, so the output doesn't depend on the map order.
+func (c Colors) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+
+	for _, ch := range c.Chars() {
+		var key, value yaml.Node
+		if err := key.Encode(ch); err != nil {
+			return nil, err
+		}
+
+		if err := value.Encode(c[ch]); err != nil {
+			return nil, err
+		}
+
+		node.Content = append(node.Content, &key, &value)
+	}
+
+	return node, nil
+}
+
+// Default chars of monochrome puzzles.
+const (
+	BackgroundChar = Char('.')
+	ForegroundChar = Char('x')
+)
+
+// MonochromeColors returns default colors of monochrome puzzles: white background and black foreground.
+func MonochromeColors() Colors {
+	return Colors{
+		BackgroundChar: Color{R: 255, G: 255, B: 255},
+		ForegroundChar: Color{},
+	}
+}
+
 // Char uniquely identifies a color.
 type Char rune
 
@@ -64,6 +147,11 @@ func (c *Char) UnmarshalText(b []byte) error {
 	return nil
 }
 
+// UnmarshalYAML decodes the character from YAML node, syntax errors are reported with node position.
+func (c *Char) UnmarshalYAML(node *yaml.Node) error {
+	return unmarshalScalar(node, c.UnmarshalText)
+}
+
 // Color in RGB.
 type Color struct {
 	R, G, B uint8
@@ -77,6 +165,10 @@ func (c Color) MarshalText() ([]byte, error) {
 // UnmarshalText decodes the color from UTF-8-encoded hex string.
 func (c *Color) UnmarshalText(b []byte) (err error) {
 	s := string(b)
+	if s == "" {
+		return fmt.Errorf(`%w: color hex shouldn't be empty`, errors.ErrSyntax)
+	}
+
 	if s[0] != '#' {
 		return fmt.Errorf(`%w: color hex "%s" should start with "#"`, errors.ErrSyntax, s)
 	}
@@ -112,6 +204,11 @@ func (c *Color) UnmarshalText(b []byte) (err error) {
 	return
 }
 
+// UnmarshalYAML decodes the color from YAML node, syntax errors are reported with node position.
+func (c *Color) UnmarshalYAML(node *yaml.Node) error {
+	return unmarshalScalar(node, c.UnmarshalText)
+}
+
 // RGBA returns the alpha-premultiplied red, green, blue and alpha values for the color.
 func (c Color) RGBA() (r, g, b, a uint32) {
 	r = uint32(c.R)
@@ -131,8 +228,8 @@ func (c Color) RGBA() (r, g, b, a uint32) {
 
 // Clue defines a clue used in the puzzle.
 type Clue struct {
-	Columns []Line `yaml:"columns,flow"`
-	Rows    []Line `yaml:"rows,flow"`
+	Columns []Line `yaml:"columns,flow" json:"columns"`
+	Rows    []Line `yaml:"rows,flow" json:"rows"`
 }
 
 // Line of clue items.
@@ -140,9 +237,140 @@ type Line []Item
 
 // Item of the clue.
 type Item struct {
-	Color Char `yaml:"color"`
-	Count int  `yaml:"count"`
+	Color Char `yaml:"color" json:"color"`
+	Count int  `yaml:"count" json:"count"`
 }
 
 // Goal of the puzzle.
 type Goal [][]Char
+
+// MarshalYAML encodes every row as a string of chars, so the goal looks like the picture.
+func (g Goal) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.SequenceNode}
+
+	for _, row := range g {
+		node.Content = append(node.Content, &yaml.Node{
+			Kind:  yaml.ScalarNode,
+			Tag:   "!!str",
+			Value: string(row),
+		})
+	}
+
+	return node, nil
+}
+
+// UnmarshalYAML decodes rows given either as strings of chars or as lists of chars.
+func (g *Goal) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.SequenceNode {
+		return &errors.SyntaxError{
+			Position: errors.Position{Line: node.Line, Column: node.Column},
+			Err:      fmt.Errorf(`%w: goal should be a list of rows`, errors.ErrSyntax),
+		}
+	}
+
+	goal := make(Goal, 0, len(node.Content))
+
+	for _, rowNode := range node.Content {
+		var row []Char
+
+		switch {
+		case rowNode.Kind == yaml.ScalarNode && rowNode.ShortTag() == "!!null":
+		case rowNode.Kind == yaml.ScalarNode:
+			if err := unmarshalScalar(rowNode, func(b []byte) (err error) {
+				row, err = parseRow(string(b))
+
+				return err
+			}); err != nil {
+				return err
+			}
+		default:
+			if err := rowNode.Decode(&row); err != nil {
+				return err
+			}
+		}
+
+		goal = append(goal, row)
+	}
+
+	*g = goal
+
+	return nil
+}
+
+// MarshalJSON encodes every row as a string of chars.
+func (g Goal) MarshalJSON() ([]byte, error) {
+	rows := make([]string, 0, len(g))
+	for _, row := range g {
+		rows = append(rows, string(row))
+	}
+
+	return json.Marshal(rows)
+}
+
+// UnmarshalJSON decodes rows given either as strings of chars or as arrays of chars.
+func (g *Goal) UnmarshalJSON(b []byte) error {
+	var rows []json.RawMessage
+	if err := json.Unmarshal(b, &rows); err != nil {
+		return err
+	}
+
+	goal := make(Goal, 0, len(rows))
+
+	for _, raw := range rows {
+		var row []Char
+
+		if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '"' {
+			var s string
+			if err := json.Unmarshal(trimmed, &s); err != nil {
+				return err
+			}
+
+			var err error
+			if row, err = parseRow(s); err != nil {
+				return err
+			}
+		} else if err := json.Unmarshal(raw, &row); err != nil {
+			return err
+		}
+
+		goal = append(goal, row)
+	}
+
+	*g = goal
+
+	return nil
+}
+
+// parseRow returns chars of the goal row string.
+func parseRow(s string) ([]Char, error) {
+	row := make([]Char, 0, utf8.RuneCountInString(s))
+
+	for _, r := range s {
+		var ch Char
+		if err := ch.UnmarshalText([]byte(string(r))); err != nil {
+			return nil, err
+		}
+
+		row = append(row, ch)
+	}
+
+	return row, nil
+}
+
+func unmarshalScalar(node *yaml.Node, unmarshalText func([]byte) error) error {
+	var err error
+	if node.Kind != yaml.ScalarNode {
+		err = fmt.Errorf(`%w: should be a scalar`, errors.ErrSyntax)
+	} else {
+		err = unmarshalText([]byte(node.Value))
+	}
+
+	if err != nil {
+		return &errors.SyntaxError{
+			Position: errors.Position{Line: node.Line, Column: node.Column},
+			Err:      err,
+		}
+	}
+
+	return nil
+}
@@ -1,12 +1,14 @@
 package ast_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
 	"github.com/alexeyco/hanjie/ast"
 	"github.com/alexeyco/hanjie/errors"
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
 )
 
 func TestChar_MarshalText(t *testing.T) {
@@ -55,6 +57,42 @@ func TestChar_UnmarshalText(t *testing.T) {
 	})
 }
 
+func TestChar_UnmarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		var ch ast.Char
+		err := yaml.Unmarshal([]byte("x"), &ch)
+
+		assert.NoError(t, err)
+		assert.Equal(t, ast.Char('x'), ch)
+	})
+
+	t.Run("ErrorWithPosition", func(t *testing.T) {
+		t.Parallel()
+
+		var chars []ast.Char
+		err := yaml.Unmarshal([]byte("- x\n- xx\n"), &chars)
+
+		var syntaxError *errors.SyntaxError
+
+		assert.ErrorIs(t, err, errors.ErrSyntax)
+		assert.ErrorAs(t, err, &syntaxError)
+		assert.Equal(t, errors.Position{Line: 2, Column: 3}, syntaxError.Position)
+	})
+
+	t.Run("ErrorCauseNotScalar", func(t *testing.T) {
+		t.Parallel()
+
+		var ch ast.Char
+		err := yaml.Unmarshal([]byte("[x]"), &ch)
+
+		assert.ErrorIs(t, err, errors.ErrSyntax)
+	})
+}
+
 func TestColor_MarshalText(t *testing.T) {
 	t.Parallel()
 
@@ -146,6 +184,11 @@ func TestColor_UnmarshalText(t *testing.T) {
 			expected: &ast.Color{},
 			err:      errors.ErrSyntax,
 		},
+		{
+			b:        []byte(""),
+			expected: &ast.Color{},
+			err:      errors.ErrSyntax,
+		},
 	}
 
 	for _, testDatum := range testData {
@@ -167,6 +210,64 @@ func TestColor_UnmarshalText(t *testing.T) {
 	}
 }
 
+func TestColor_UnmarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		var color ast.Color
+		err := yaml.Unmarshal([]byte("'#fff'"), &color)
+
+		assert.NoError(t, err)
+		assert.Equal(t, ast.Color{R: 255, G: 255, B: 255}, color)
+	})
+
+	t.Run("ErrorWithPosition", func(t *testing.T) {
+		t.Parallel()
+
+		var colors ast.Colors
+		err := yaml.Unmarshal([]byte(".: '#fff'\nx: wrong\n"), &colors)
+
+		var syntaxError *errors.SyntaxError
+
+		assert.ErrorIs(t, err, errors.ErrSyntax)
+		assert.ErrorAs(t, err, &syntaxError)
+		assert.Equal(t, errors.Position{Line: 2, Column: 4}, syntaxError.Position)
+	})
+}
+
+func TestColors_Chars(t *testing.T) {
+	t.Parallel()
+
+	colors := ast.Colors{
+		ast.Char('x'): ast.Color{},
+		ast.Char('.'): ast.Color{},
+		ast.Char('a'): ast.Color{},
+	}
+
+	assert.Equal(t, []ast.Char{ast.Char('.'), ast.Char('a'), ast.Char('x')}, colors.Chars())
+}
+
+func TestColors_MarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	colors := ast.Colors{}
+	for ch := 'h'; ch >= 'a'; ch-- {
+		colors[ast.Char(ch)] = ast.Color{R: uint8(ch)}
+	}
+
+	expected := ""
+	for ch := 'a'; ch <= 'h'; ch++ {
+		expected += fmt.Sprintf("%c: '#%02x0000'\n", ch, ch)
+	}
+
+	actual, err := yaml.Marshal(colors)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(actual))
+}
+
 func TestColor_RGBA(t *testing.T) {
 	t.Parallel()
 
@@ -203,3 +304,123 @@ func TestColor_RGBA(t *testing.T) {
 		})
 	}
 }
+
+func TestGoal_MarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	goal := ast.Goal{{'x', 'x', '.'}, {'.', 'r', 'r'}}
+
+	actual, err := yaml.Marshal(goal)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "- xx.\n- .rr\n", string(actual))
+}
+
+func TestGoal_UnmarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	testData := [...]struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "Strings",
+			input: "- xx.\n- .rr\n",
+		},
+		{
+			name:  "Lists",
+			input: "[[x, x, .], [., r, r]]",
+		},
+		{
+			name:  "Mixed",
+			input: "- xx.\n- [., r, r]\n",
+		},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			var goal ast.Goal
+			err := yaml.Unmarshal([]byte(testDatum.input), &goal)
+
+			assert.NoError(t, err)
+			assert.Equal(t, ast.Goal{{'x', 'x', '.'}, {'.', 'r', 'r'}}, goal)
+		})
+	}
+
+	errorData := [...]struct {
+		name     string
+		input    string
+		position errors.Position
+	}{
+		{
+			name:     "ErrorCauseNotList",
+			input:    "xx.",
+			position: errors.Position{Line: 1, Column: 1},
+		},
+		{
+			name:     "ErrorCauseWrongChar",
+			input:    "- xx.\n- .+r\n",
+			position: errors.Position{Line: 2, Column: 3},
+		},
+	}
+
+	for _, testDatum := range errorData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			var goal ast.Goal
+			err := yaml.Unmarshal([]byte(testDatum.input), &goal)
+
+			var syntaxError *errors.SyntaxError
+
+			assert.ErrorIs(t, err, errors.ErrSyntax)
+			assert.ErrorAs(t, err, &syntaxError)
+			assert.Equal(t, testDatum.position, syntaxError.Position)
+		})
+	}
+}
+
+func TestGoal_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	actual, err := json.Marshal(ast.Goal{{'x', 'x', '.'}, {'.', 'r', 'r'}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `["xx.",".rr"]`, string(actual))
+}
+
+func TestGoal_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		var goal ast.Goal
+		err := json.Unmarshal([]byte(`["xx.", [".", "r", "r"]]`), &goal)
+
+		assert.NoError(t, err)
+		assert.Equal(t, ast.Goal{{'x', 'x', '.'}, {'.', 'r', 'r'}}, goal)
+	})
+
+	t.Run("ErrorCauseWrongChar", func(t *testing.T) {
+		t.Parallel()
+
+		var goal ast.Goal
+		err := json.Unmarshal([]byte(`["x+."]`), &goal)
+
+		assert.ErrorIs(t, err, errors.ErrSyntax)
+	})
+}
+
+func TestCollection_HasMetadata(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, ast.Collection{Puzzles: ast.PuzzleSet{{Title: "Puzzle"}}}.HasMetadata())
+	assert.True(t, ast.Collection{Revision: "1"}.HasMetadata())
+}
@@ -0,0 +1,130 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExtensionPrefix starts keys of puzzle extensions.
+const ExtensionPrefix = "x-"
+
+// IsExtension reports whether the key is a key of puzzle extension.
+func IsExtension(key string) bool {
+	return strings.HasPrefix(key, ExtensionPrefix)
+}
+
+// encodeExtensions appends extensions to the puzzle mapping sorted by keys.
+func encodeExtensions(node *yaml.Node, extensions map[string]interface{}) error {
+	for _, key := range extensionKeys(extensions) {
+		var value yaml.Node
+		if err := value.Encode(extensions[key]); err != nil {
+			return err
+		}
+
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &value)
+	}
+
+	return nil
+}
+
+// decodeExtensions collects extensions of the puzzle mapping, other unknown keys are ignored.
+func decodeExtensions(node *yaml.Node, extensions *map[string]interface{}) error {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		if !IsExtension(key) {
+			continue
+		}
+
+		var value interface{}
+		if err := node.Content[i+1].Decode(&value); err != nil {
+			return err
+		}
+
+		if *extensions == nil {
+			*extensions = map[string]interface{}{}
+		}
+
+		(*extensions)[key] = value
+	}
+
+	return nil
+}
+
+// marshalExtensions appends extensions sorted by keys to the JSON object.
+func marshalExtensions(b []byte, extensions map[string]interface{}) ([]byte, error) {
+	keys := extensionKeys(extensions)
+	if len(keys) == 0 {
+		return b, nil
+	}
+
+	var buf bytes.Buffer
+	buf.Write(bytes.TrimSuffix(b, []byte("}")))
+
+	for i, key := range keys {
+		if i > 0 || !bytes.Equal(b, []byte("{}")) {
+			buf.WriteByte(',')
+		}
+
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := json.Marshal(extensions[key])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// unmarshalExtensions collects extensions of the JSON object, other unknown keys are ignored.
+func unmarshalExtensions(b []byte, extensions *map[string]interface{}) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+
+	for key, raw := range fields {
+		if !IsExtension(key) {
+			continue
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return err
+		}
+
+		if *extensions == nil {
+			*extensions = map[string]interface{}{}
+		}
+
+		(*extensions)[key] = value
+	}
+
+	return nil
+}
+
+func extensionKeys(extensions map[string]interface{}) []string {
+	keys := make([]string, 0, len(extensions))
+	for key := range extensions {
+		if IsExtension(key) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
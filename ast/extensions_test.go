@@ -0,0 +1,73 @@
+package ast_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestIsExtension(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, ast.IsExtension("x-rating"))
+	assert.False(t, ast.IsExtension("rating"))
+}
+
+func TestPuzzle_Extensions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("YAML", func(t *testing.T) {
+		t.Parallel()
+
+		input := "title: Puzzle\nfoo: bar\nx-rating: 5\nx-tags: [cat, dog]\n"
+
+		var puzzle ast.Puzzle
+		err := yaml.Unmarshal([]byte(input), &puzzle)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"x-rating": 5,
+			"x-tags":   []interface{}{"cat", "dog"},
+		}, puzzle.Extensions)
+
+		actual, err := yaml.Marshal(puzzle)
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(actual), "x-rating: 5\nx-tags:\n    - cat\n    - dog\n")
+		assert.NotContains(t, string(actual), "foo")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+
+		input := `{"title": "Puzzle", "foo": "bar", "x-rating": 5, "x-play-count": 10}`
+
+		var puzzle ast.Puzzle
+		err := json.Unmarshal([]byte(input), &puzzle)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"x-play-count": float64(10),
+			"x-rating":     float64(5),
+		}, puzzle.Extensions)
+
+		actual, err := json.Marshal(puzzle)
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(actual), `,"x-play-count":10,"x-rating":5}`)
+		assert.NotContains(t, string(actual), "foo")
+	})
+
+	t.Run("None", func(t *testing.T) {
+		t.Parallel()
+
+		var puzzle ast.Puzzle
+		err := yaml.Unmarshal([]byte("title: Puzzle\n"), &puzzle)
+
+		assert.NoError(t, err)
+		assert.Nil(t, puzzle.Extensions)
+	})
+}
@@ -0,0 +1,79 @@
+//go:build go1.18
+// +build go1.18
+
+package ast_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func FuzzParseLine(f *testing.F) {
+	for _, seed := range []string{"", "0", "2 1 3", "2x 1r 3x", " 10 ", "1 x", "99999999999999999999"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		line, err := ast.ParseLine(s)
+		if err != nil {
+			return
+		}
+
+		formatted, ok := line.Format(true)
+		if !ok {
+			return
+		}
+
+		reparsed, err := ast.ParseLine(formatted)
+
+		assert.NoError(t, err)
+		assert.Equal(t, len(line), len(reparsed))
+	})
+}
+
+func FuzzColor_UnmarshalText(f *testing.F) {
+	for _, seed := range []string{"", "#", "#fff", "#FFFFFF", "#zz", "wrong"} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		var color ast.Color
+		if err := color.UnmarshalText(b); err != nil {
+			return
+		}
+
+		text, err := color.MarshalText()
+
+		assert.NoError(t, err)
+		assert.Len(t, text, 7)
+	})
+}
+
+func FuzzPuzzle_Unmarshal(f *testing.F) {
+	for _, seed := range []string{
+		"title: Puzzle\nbackground: .\ncolors: {.: '#fff', x: '#000'}\nclue:\n  rows: [\"2 1\"]\ngoal: [x.x]\n",
+		"colors: {.: ''}\nclue: {rows: [[{color: x, count: 1}]]}\n",
+		`{"background": ".", "colors": {".": "#fff", "x": "#000"}, "clue": {"rows": ["2 1"]}, "goal": [["x"]]}`,
+		`{"x-rating": 5, "goal": ["x."]}`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var puzzle ast.Puzzle
+		if err := yaml.Unmarshal(data, &puzzle); err == nil {
+			_, err = yaml.Marshal(puzzle)
+			assert.NoError(t, err)
+		}
+
+		puzzle = ast.Puzzle{}
+		if err := json.Unmarshal(data, &puzzle); err == nil {
+			_, err = json.Marshal(puzzle)
+			assert.NoError(t, err)
+		}
+	})
+}
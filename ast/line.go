@@ -0,0 +1,328 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/alexeyco/hanjie/errors"
+)
+
+// ImplicitChar is the color of clue items written without color in the compact notation, e.g. "2 1 3".
+// Puzzle decoding replaces it with the only non-background color of the puzzle.
+const ImplicitChar = Char(0)
+
+// ParseLine parses the compact notation of the line: space-separated counts followed by color chars,
+// e.g. "2x 1r 3x". Items written without color, e.g. "2 1 3", get ImplicitChar. Zero counts are skipped.
+func ParseLine(s string) (Line, error) {
+	line := Line{}
+
+	for _, token := range strings.Fields(s) {
+		digits := strings.IndexFunc(token, func(r rune) bool {
+			return r < '0' || r > '9'
+		})
+		if digits < 0 {
+			digits = len(token)
+		}
+
+		count, err := strconv.Atoi(token[:digits])
+		if err != nil {
+			return nil, fmt.Errorf(`%w: wrong clue item "%s": should be a count followed by a color char`, errors.ErrSyntax, token)
+		}
+
+		color := ImplicitChar
+		if suffix := token[digits:]; suffix != "" {
+			if utf8.RuneCountInString(suffix) != 1 {
+				return nil, fmt.Errorf(`%w: wrong clue item "%s": should be a count followed by a color char`, errors.ErrSyntax, token)
+			}
+
+			if err = color.UnmarshalText([]byte(suffix)); err != nil {
+				return nil, err
+			}
+		}
+
+		if count > 0 {
+			line = append(line, Item{Color: color, Count: count})
+		}
+	}
+
+	return line, nil
+}
+
+// Format returns the compact notation of the line. Colors are omitted if withColors is false.
+// It reports false if the line can't be written in the compact notation, i.e. a color char is a digit or a space,
+// or a count isn't positive, since the notation skips zero counts.
+func (l Line) Format(withColors bool) (string, bool) {
+	tokens := make([]string, 0, len(l))
+
+	for _, item := range l {
+		if item.Count <= 0 {
+			return "", false
+		}
+
+		token := strconv.Itoa(item.Count)
+
+		if withColors {
+			if item.Color == ImplicitChar || unicode.IsDigit(rune(item.Color)) || unicode.IsSpace(rune(item.Color)) {
+				return "", false
+			}
+
+			token += string(item.Color)
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return strings.Join(tokens, " "), true
+}
+
+// MarshalYAML encodes the line in the compact notation as a double-quoted string, e.g. "2x 1r 3x".
+func (l Line) MarshalYAML() (interface{}, error) {
+	s, ok := l.Format(true)
+	if !ok {
+		return []Item(l), nil
+	}
+
+	return &yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Style: yaml.DoubleQuotedStyle,
+		Tag:   "!!str",
+		Value: s,
+	}, nil
+}
+
+// UnmarshalYAML decodes the line given either in the compact notation or as a list of items.
+func (l *Line) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode && node.ShortTag() != "!!null" {
+		return unmarshalScalar(node, func(b []byte) (err error) {
+			*l, err = ParseLine(string(b))
+
+			return err
+		})
+	}
+
+	var items []Item
+	if err := node.Decode(&items); err != nil {
+		return err
+	}
+
+	*l = items
+
+	return nil
+}
+
+// UnmarshalJSON decodes the line given either in the compact notation or as an array of items.
+func (l *Line) UnmarshalJSON(b []byte) error {
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '"' {
+		var s string
+		if err := json.Unmarshal(trimmed, &s); err != nil {
+			return err
+		}
+
+		line, err := ParseLine(s)
+		if err != nil {
+			return err
+		}
+
+		*l = line
+
+		return nil
+	}
+
+	var items []Item
+	if err := json.Unmarshal(b, &items); err != nil {
+		return err
+	}
+
+	*l = items
+
+	return nil
+}
+
+// puzzle is Puzzle without custom encoding.
+type puzzle Puzzle
+
+// MarshalYAML encodes the puzzle, clue lines of single-color puzzles are written without colors, e.g. "2 1 3".
+func (p Puzzle) MarshalYAML() (interface{}, error) {
+	var node yaml.Node
+	if err := node.Encode(puzzle(p)); err != nil {
+		return nil, err
+	}
+
+	if _, ok := p.singleColor(); ok {
+		compactClue(&node, p.Clue)
+	}
+
+	if err := encodeExtensions(&node, p.Extensions); err != nil {
+		return nil, err
+	}
+
+	return &node, nil
+}
+
+// compactClue replaces clue lines of the encoded puzzle with lines written without colors.
+func compactClue(node *yaml.Node, c Clue) {
+	clue := mappingValue(node, "clue")
+
+	for _, lines := range [...]struct {
+		key   string
+		lines []Line
+	}{
+		{key: "columns", lines: c.Columns},
+		{key: "rows", lines: c.Rows},
+	} {
+		sequence := mappingValue(clue, lines.key)
+		if sequence == nil {
+			continue
+		}
+
+		for i, n := range sequence.Content {
+			if n.Kind == yaml.ScalarNode && i < len(lines.lines) {
+				if s, ok := lines.lines[i].Format(false); ok {
+					n.Value = s
+				}
+			}
+		}
+	}
+}
+
+// UnmarshalYAML decodes the puzzle resolving colors of clue items written without color.
+func (p *Puzzle) UnmarshalYAML(node *yaml.Node) error {
+	if err := node.Decode((*puzzle)(p)); err != nil {
+		return err
+	}
+
+	if err := decodeExtensions(node, &p.Extensions); err != nil {
+		return err
+	}
+
+	if err := p.resolveImplicitColors(); err != nil {
+		position := node
+		if clue := mappingValue(node, "clue"); clue != nil {
+			position = clue
+		}
+
+		return &errors.SyntaxError{
+			Position: errors.Position{Line: position.Line, Column: position.Column},
+			Err:      err,
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalJSON decodes the puzzle resolving colors of clue items written without color.
+func (p *Puzzle) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, (*puzzle)(p)); err != nil {
+		return err
+	}
+
+	if err := unmarshalExtensions(b, &p.Extensions); err != nil {
+		return err
+	}
+
+	return p.resolveImplicitColors()
+}
+
+// MarshalJSON encodes the puzzle followed by its extensions.
+// HTML characters are escaped by the caller's encoder, so it's up to its SetEscapeHTML.
+func (p Puzzle) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(puzzle(p)); err != nil {
+		return nil, err
+	}
+
+	return marshalExtensions(bytes.TrimSpace(buf.Bytes()), p.Extensions)
+}
+
+// singleColor returns the only non-background color if every clue item is of that color.
+func (p Puzzle) singleColor() (Char, bool) {
+	color, err := p.implicitColor()
+	if err != nil {
+		return ImplicitChar, false
+	}
+
+	for _, lines := range [...][]Line{p.Clue.Columns, p.Clue.Rows} {
+		for _, line := range lines {
+			for _, item := range line {
+				if item.Color != color {
+					return ImplicitChar, false
+				}
+			}
+		}
+	}
+
+	return color, true
+}
+
+func (p *Puzzle) resolveImplicitColors() error {
+	var color Char
+
+	for _, lines := range [...][]Line{p.Clue.Columns, p.Clue.Rows} {
+		for _, line := range lines {
+			for i := range line {
+				if line[i].Color != ImplicitChar {
+					continue
+				}
+
+				if color == ImplicitChar {
+					var err error
+					if color, err = p.implicitColor(); err != nil {
+						return err
+					}
+				}
+
+				line[i].Color = color
+			}
+		}
+	}
+
+	return nil
+}
+
+// implicitColor returns the only non-background color of the puzzle.
+func (p Puzzle) implicitColor() (Char, error) {
+	color := ImplicitChar
+
+	for ch := range p.Colors {
+		if ch == p.Background {
+			continue
+		}
+
+		if color != ImplicitChar {
+			return ImplicitChar, fmt.Errorf("%w: clue items without color are allowed in single-color puzzles only", errors.ErrSyntax)
+		}
+
+		color = ch
+	}
+
+	if color == ImplicitChar {
+		return ImplicitChar, fmt.Errorf("%w: clue items without color need a non-background color", errors.ErrSyntax)
+	}
+
+	return color, nil
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
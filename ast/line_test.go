@@ -0,0 +1,241 @@
+package ast_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseLine(t *testing.T) {
+	t.Parallel()
+
+	testData := [...]struct {
+		name     string
+		input    string
+		expected ast.Line
+	}{
+		{
+			name:     "Colored",
+			input:    "2x 1r  3x",
+			expected: ast.Line{{Color: 'x', Count: 2}, {Color: 'r', Count: 1}, {Color: 'x', Count: 3}},
+		},
+		{
+			name:     "Implicit",
+			input:    " 2 10 ",
+			expected: ast.Line{{Color: ast.ImplicitChar, Count: 2}, {Color: ast.ImplicitChar, Count: 10}},
+		},
+		{
+			name:     "Empty",
+			input:    "",
+			expected: ast.Line{},
+		},
+		{
+			name:     "Zero",
+			input:    "0",
+			expected: ast.Line{},
+		},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := ast.ParseLine(testDatum.input)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testDatum.expected, actual)
+		})
+	}
+
+	for _, input := range []string{"x", "2xy", "-1", "2+"} {
+		input := input
+
+		t.Run("Error "+input, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := ast.ParseLine(input)
+
+			assert.Nil(t, actual)
+			assert.ErrorIs(t, err, errors.ErrSyntax)
+		})
+	}
+}
+
+func TestLine_Format(t *testing.T) {
+	t.Parallel()
+
+	line := ast.Line{{Color: 'x', Count: 2}, {Color: 'r', Count: 1}}
+
+	actual, ok := line.Format(true)
+
+	assert.True(t, ok)
+	assert.Equal(t, "2x 1r", actual)
+
+	actual, ok = line.Format(false)
+
+	assert.True(t, ok)
+	assert.Equal(t, "2 1", actual)
+
+	_, ok = ast.Line{{Color: '1', Count: 2}}.Format(true)
+
+	assert.False(t, ok)
+
+	_, ok = ast.Line{{Color: 'x', Count: 2}, {Color: 'x', Count: 0}}.Format(false)
+
+	assert.False(t, ok)
+
+	_, ok = ast.Line{{Color: 'x', Count: -1}}.Format(true)
+
+	assert.False(t, ok)
+}
+
+func TestLine_MarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	lines := []ast.Line{
+		{{Color: 'x', Count: 2}, {Color: 'r', Count: 1}},
+		{},
+		{{Color: '1', Count: 1}},
+		{{Color: 'x', Count: 0}},
+	}
+
+	actual, err := yaml.Marshal(lines)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "- \"2x 1r\"\n- \"\"\n- - color: \"1\"\n    count: 1\n- - color: x\n    count: 0\n", string(actual))
+
+	var decoded []ast.Line
+	err = yaml.Unmarshal(actual, &decoded)
+
+	assert.NoError(t, err)
+	assert.Equal(t, lines, decoded)
+}
+
+func TestLine_UnmarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		var lines []ast.Line
+		err := yaml.Unmarshal([]byte(`["2x 1r", [{color: x, count: 1}]]`), &lines)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []ast.Line{
+			{{Color: 'x', Count: 2}, {Color: 'r', Count: 1}},
+			{{Color: 'x', Count: 1}},
+		}, lines)
+	})
+
+	t.Run("ErrorWithPosition", func(t *testing.T) {
+		t.Parallel()
+
+		var lines []ast.Line
+		err := yaml.Unmarshal([]byte("- 2x\n- 2x 1\n- 2xx\n"), &lines)
+
+		var syntaxError *errors.SyntaxError
+
+		assert.ErrorIs(t, err, errors.ErrSyntax)
+		assert.ErrorAs(t, err, &syntaxError)
+		assert.Equal(t, errors.Position{Line: 3, Column: 3}, syntaxError.Position)
+	})
+}
+
+func TestLine_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	var lines []ast.Line
+	err := json.Unmarshal([]byte(`["2x 1r", [{"color": "x", "count": 1}]]`), &lines)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []ast.Line{
+		{{Color: 'x', Count: 2}, {Color: 'r', Count: 1}},
+		{{Color: 'x', Count: 1}},
+	}, lines)
+
+	err = json.Unmarshal([]byte(`["2x x"]`), &lines)
+
+	assert.ErrorIs(t, err, errors.ErrSyntax)
+}
+
+func TestPuzzle_MarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	puzzle := ast.Puzzle{
+		Title:      "Puzzle",
+		Background: '.',
+		Colors:     ast.MonochromeColors(),
+		Clue: ast.Clue{
+			Columns: []ast.Line{{{Color: 'x', Count: 1}}},
+			Rows:    []ast.Line{{{Color: 'x', Count: 1}}},
+		},
+	}
+
+	actual, err := yaml.Marshal(puzzle)
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(actual), "columns: [\"1\"]\n")
+
+	puzzle.Colors['r'] = ast.Color{R: 255}
+
+	actual, err = yaml.Marshal(puzzle)
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(actual), "columns: [\"1x\"]\n")
+}
+
+func TestPuzzle_UnmarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		var puzzle ast.Puzzle
+		err := yaml.Unmarshal([]byte("background: .\ncolors: {.: '#fff', x: '#000'}\nclue:\n  rows: [\"2 1\"]\n"), &puzzle)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []ast.Line{{{Color: 'x', Count: 2}, {Color: 'x', Count: 1}}}, puzzle.Clue.Rows)
+	})
+
+	t.Run("OkDifficulty", func(t *testing.T) {
+		t.Parallel()
+
+		input := "background: .\ncolors: {.: '#fff', x: '#000'}\nclue:\n  rows: [\"1\"]\n" +
+			"difficulty: {score: 19, label: medium, lines: 9, probes: 1, guesses: 0}\n"
+
+		var puzzle ast.Puzzle
+		err := yaml.Unmarshal([]byte(input), &puzzle)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &ast.Difficulty{Score: 19, Label: "medium", Lines: 9, Probes: 1}, puzzle.Difficulty)
+	})
+
+	t.Run("ErrorCauseMultiColored", func(t *testing.T) {
+		t.Parallel()
+
+		var puzzle ast.Puzzle
+		err := yaml.Unmarshal([]byte("background: .\ncolors: {.: '#fff', x: '#000', r: '#f00'}\nclue:\n  rows: [\"2 1r\"]\n"), &puzzle)
+
+		var syntaxError *errors.SyntaxError
+
+		assert.ErrorIs(t, err, errors.ErrSyntax)
+		assert.ErrorAs(t, err, &syntaxError)
+		assert.Equal(t, errors.Position{Line: 4, Column: 3}, syntaxError.Position)
+	})
+}
+
+func TestPuzzle_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	var puzzle ast.Puzzle
+	err := json.Unmarshal([]byte(`{"background": ".", "colors": {".": "#fff", "x": "#000"}, "clue": {"rows": ["2 1"]}}`), &puzzle)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []ast.Line{{{Color: 'x', Count: 2}, {Color: 'x', Count: 1}}}, puzzle.Clue.Rows)
+}
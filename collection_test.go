@@ -0,0 +1,173 @@
+package hanjie_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alexeyco/hanjie"
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var collectionHeader = `version: 2
+name: Animals
+author:
+  name: John Doe
+  id: johnDoe
+license: CC-BY-4.0
+revision: 1.2.0
+description: Puzzles with animals
+`
+
+func expectedCollection() *ast.Collection {
+	return &ast.Collection{
+		Name:        "Animals",
+		Author:      &ast.Author{Name: "John Doe", ID: "johnDoe"},
+		License:     "CC-BY-4.0",
+		Revision:    "1.2.0",
+		Description: "Puzzles with animals",
+		Puzzles:     *expectedPuzzleSet,
+	}
+}
+
+func TestReadCollection(t *testing.T) {
+	t.Parallel()
+
+	testData := [...]struct {
+		name     string
+		input    string
+		options  []hanjie.Option
+		expected *ast.Collection
+	}{
+		{
+			name:     "Ok",
+			input:    strings.Replace(expectedString, "version: 2\n", collectionHeader, 1),
+			expected: expectedCollection(),
+		},
+		{
+			name:     "OkStrict",
+			input:    strings.Replace(expectedString, "version: 2\n", collectionHeader, 1),
+			options:  []hanjie.Option{hanjie.Strict},
+			expected: expectedCollection(),
+		},
+		{
+			name:     "OkWithoutMetadata",
+			input:    expectedString,
+			expected: &ast.Collection{Puzzles: *expectedPuzzleSet},
+		},
+		{
+			name:     "OkBareList",
+			input:    legacyString,
+			expected: &ast.Collection{Puzzles: *expectedPuzzleSet},
+		},
+		{
+			name: "OkJSON",
+			input: `{"version": 2, "name": "Animals", "author": {"name": "John Doe", "id": "johnDoe"},
+"license": "CC-BY-4.0", "revision": "1.2.0", "description": "Puzzles with animals", "puzzles": ` + expectedJSONString + `}`,
+			options:  []hanjie.Option{hanjie.WithFormat(hanjie.FormatJSON), hanjie.Strict},
+			expected: expectedCollection(),
+		},
+		{
+			name:     "OkJSONBareList",
+			input:    expectedJSONString,
+			options:  []hanjie.Option{hanjie.WithFormat(hanjie.FormatJSON)},
+			expected: &ast.Collection{Puzzles: *expectedPuzzleSet},
+		},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := hanjie.ReadCollection(strings.NewReader(testDatum.input), testDatum.options...)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testDatum.expected, actual)
+		})
+	}
+
+	t.Run("ErrorCauseUnknownField", func(t *testing.T) {
+		t.Parallel()
+
+		input := strings.Replace(expectedString, "version: 2\n", "version: 2\ntitle: Animals\n", 1)
+
+		actual, err := hanjie.ReadCollection(strings.NewReader(input), hanjie.Strict)
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrUnknownField)
+	})
+}
+
+func TestWriteCollection(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		err := hanjie.WriteCollection(&buf, expectedCollection())
+
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Replace(expectedString, "version: 2\n", collectionHeader, 1), buf.String())
+	})
+
+	t.Run("OkNilCollection", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		err := hanjie.WriteCollection(&buf, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "version: 2\npuzzles: []\n", buf.String())
+	})
+
+	t.Run("OkVersion1", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		err := hanjie.WriteCollection(&buf, expectedCollection(), hanjie.WithVersion(1))
+
+		assert.NoError(t, err)
+		assert.Equal(t, legacyString, buf.String())
+	})
+
+	t.Run("OkJSON", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		err := hanjie.WriteCollection(&buf, expectedCollection(), hanjie.WithFormat(hanjie.FormatJSON))
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(buf.String(), "{\n  \"version\": 2,\n  \"name\": \"Animals\",\n"))
+
+		actual, err := hanjie.ReadCollection(&buf, hanjie.WithFormat(hanjie.FormatJSON))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedCollection(), actual)
+	})
+
+	t.Run("OkDocument", func(t *testing.T) {
+		t.Parallel()
+
+		input := "# Collection\n" + strings.Replace(expectedString, "version: 2\n", collectionHeader, 1)
+		input = strings.Replace(input, "name: Animals\n", "name: Animals # working name\n", 1)
+
+		document, err := hanjie.ReadDocument(strings.NewReader(input))
+		assert.NoError(t, err)
+
+		document.Revision = "1.3.0"
+
+		var buf bytes.Buffer
+
+		assert.NoError(t, document.Write(&buf))
+		assert.Equal(t, strings.Replace(input, "revision: 1.2.0", "revision: 1.3.0", 1), buf.String())
+	})
+}
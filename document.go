@@ -0,0 +1,219 @@
+package hanjie
+
+import (
+	"io"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/alexeyco/hanjie/ast"
+)
+
+// Document is a collection of puzzles read from YAML along with the source nodes. Writing the document back
+// keeps comments of the source, so annotations written by hand survive editing puzzles programmatically.
+type Document struct {
+	ast.Collection
+	source  *yaml.Node
+	puzzles *yaml.Node
+	// nodes are the source nodes of Puzzles as read, puzzles dropped by KeepValid are skipped.
+	nodes []*yaml.Node
+}
+
+// ReadDocument reads YAML document from io.Reader, puzzles are validated the same way Read does.
+func ReadDocument(r io.Reader, options ...Option) (*Document, error) {
+	o := newOptions()
+	for _, opt := range options {
+		opt(&o)
+	}
+
+	limited := limitReader(r, o)
+
+	source, puzzles, collection, err := decodeYAML(limited, o)
+	if err != nil {
+		return nil, limited.cause(err)
+	}
+
+	puzzleNode := func(index int) *yaml.Node {
+		return child(puzzles, strconv.Itoa(index))
+	}
+
+	if err = checkLimits(o, collection.Puzzles); err != nil {
+		locate(o, puzzleNode, err)
+
+		return nil, err
+	}
+
+	valid, indexes, err := validateIndexed(o, collection.Puzzles)
+	locate(o, puzzleNode, err)
+
+	if err != nil && !o.KeepValid {
+		return nil, err
+	}
+
+	nodes := make([]*yaml.Node, len(indexes))
+	for i, index := range indexes {
+		nodes[i] = puzzleNode(index)
+	}
+
+	collection.Puzzles = valid
+
+	return &Document{
+		Collection: *collection,
+		source:     source,
+		puzzles:    puzzles,
+		nodes:      nodes,
+	}, err
+}
+
+// Write writes the collection of the document in YAML to io.Writer, puzzles are validated the same way Write does.
+// Comments of the source are moved to the written nodes: puzzles are matched by ID, and fields of puzzles
+// are matched by keys and indexes. Puzzles without ID are matched by their index in Puzzles as read,
+// so their comments are dropped once puzzles are added to or removed from Puzzles.
+func (d *Document) Write(w io.Writer, options ...Option) error {
+	o := newOptions()
+	for _, opt := range options {
+		opt(&o)
+	}
+
+	valid, indexes, err := validateIndexed(o, writable(o, d.Puzzles))
+	if err != nil && !o.KeepValid {
+		return err
+	}
+
+	var puzzles yaml.Node
+	if encodeErr := puzzles.Encode(valid); encodeErr != nil {
+		return encodeErr
+	}
+
+	d.mergePuzzleComments(&puzzles, valid, indexes)
+
+	document, encodeErr := encodePuzzles(&puzzles, &d.Collection, o)
+	if encodeErr != nil {
+		return encodeErr
+	}
+
+	if writeErr := encodeYAML(w, d.withComments(document)); writeErr != nil {
+		return writeErr
+	}
+
+	return err
+}
+
+// mergePuzzleComments copies comments of the source puzzles to the matching encoded puzzles,
+// indexes are the indexes of the written puzzles in Puzzles.
+func (d *Document) mergePuzzleComments(puzzles *yaml.Node, puzzleSet ast.PuzzleSet, indexes []int) {
+	if d.puzzles == nil {
+		return
+	}
+
+	byID := map[string]*yaml.Node{}
+	for _, node := range d.puzzles.Content {
+		if id := child(node, "id"); id != nil && id.Value != "" {
+			byID[id.Value] = resolve(node)
+		}
+	}
+
+	for i, node := range puzzles.Content {
+		source := byID[puzzleSet[i].ID]
+		if puzzleSet[i].ID == "" && len(d.nodes) == len(d.Puzzles) {
+			source = d.nodes[indexes[i]]
+		}
+
+		if source != nil {
+			mergeComments(node, source)
+		}
+	}
+}
+
+// withComments returns the document node holding comments of the source document and its envelope.
+func (d *Document) withComments(document *yaml.Node) *yaml.Node {
+	if d.source == nil {
+		return document
+	}
+
+	puzzles := document
+	if document.Kind == yaml.MappingNode {
+		puzzles = child(document, puzzlesKey)
+	}
+
+	if puzzles != nil && d.puzzles != nil {
+		copyComments(puzzles, d.puzzles)
+	}
+
+	if source := resolve(d.source); document.Kind == yaml.MappingNode && source.Kind == yaml.MappingNode {
+		copyComments(document, source)
+
+		for i := 0; i+1 < len(document.Content); i += 2 {
+			key := document.Content[i].Value
+			if sourceKey := mappingKey(source, key); sourceKey != nil {
+				copyComments(document.Content[i], sourceKey)
+			}
+
+			if key != puzzlesKey {
+				if sourceValue := child(source, key); sourceValue != nil {
+					mergeComments(document.Content[i+1], sourceValue)
+				}
+			}
+		}
+	}
+
+	return &yaml.Node{
+		Kind:        yaml.DocumentNode,
+		HeadComment: d.source.HeadComment,
+		LineComment: d.source.LineComment,
+		FootComment: d.source.FootComment,
+		Content:     []*yaml.Node{document},
+	}
+}
+
+// mergeComments copies comments of the source node and its children to the matching nodes.
+// Mapping values are matched by keys, sequence elements by indexes.
+func mergeComments(node, source *yaml.Node) {
+	source = resolve(source)
+	copyComments(node, source)
+
+	if node.Kind != source.Kind {
+		return
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := mappingKey(source, node.Content[i].Value)
+			if key == nil {
+				continue
+			}
+
+			copyComments(node.Content[i], key)
+			mergeComments(node.Content[i+1], child(source, node.Content[i].Value))
+		}
+	case yaml.SequenceNode:
+		for i := 0; i < len(node.Content) && i < len(source.Content); i++ {
+			mergeComments(node.Content[i], source.Content[i])
+		}
+	}
+}
+
+func copyComments(node, source *yaml.Node) {
+	if node.HeadComment == "" {
+		node.HeadComment = source.HeadComment
+	}
+
+	if node.LineComment == "" {
+		node.LineComment = source.LineComment
+	}
+
+	if node.FootComment == "" {
+		node.FootComment = source.FootComment
+	}
+}
+
+func mappingKey(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i]
+		}
+	}
+
+	return nil
+}
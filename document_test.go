@@ -0,0 +1,190 @@
+package hanjie_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alexeyco/hanjie"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var commentedString = `# Puzzles of the week
+version: 2
+puzzles:
+  # The first one
+  - id: id
+    source: https://foo.bar
+    author:
+      name: John Doe
+      id: johnDoe
+    copyright: '&copy; John Doe'
+    title: Puzzle # working title
+    description: Very beautiful puzzle
+    background: .
+    colors:
+      .: '#ffffff'
+      x: '#000000'
+    clue:
+      columns: ["2", "1 1", "2"]
+      rows: ["2", "1 1", "2"]
+    goal:
+      - xx.
+      - x.x # the middle row
+      - .xx
+    # rated by hand
+    x-rating: 5
+`
+
+var anonymousString = `version: 2
+puzzles:
+  # The invalid one
+  - background: .
+    colors:
+      .: '#ffffff'
+      x: '#000000'
+    clue:
+      columns: ["1"]
+      rows: ["1"]
+  # The valid one
+  - title: Puzzle
+    background: .
+    colors:
+      .: '#ffffff'
+      x: '#000000'
+    clue:
+      columns: ["1"]
+      rows: ["1"]
+`
+
+func TestReadDocument(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		document, err := hanjie.ReadDocument(strings.NewReader(commentedString))
+
+		assert.NoError(t, err)
+		assert.Len(t, document.Puzzles, 1)
+		assert.Equal(t, map[string]interface{}{"x-rating": 5}, document.Puzzles[0].Extensions)
+	})
+
+	t.Run("ErrorCauseInvalidPuzzle", func(t *testing.T) {
+		t.Parallel()
+
+		document, err := hanjie.ReadDocument(strings.NewReader(legacyString+untitledString), hanjie.WithFilename("foo.yml"))
+
+		var puzzleError *errors.PuzzleError
+
+		assert.Nil(t, document)
+		assert.ErrorIs(t, err, errors.ErrEmptyTitle)
+		assert.ErrorAs(t, err, &puzzleError)
+		assert.Equal(t, errors.Position{File: "foo.yml", Line: 17, Column: 3}, puzzleError.Position)
+	})
+}
+
+func TestDocument_Write(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		document, err := hanjie.ReadDocument(strings.NewReader(commentedString))
+		assert.NoError(t, err)
+
+		var buf bytes.Buffer
+
+		assert.NoError(t, document.Write(&buf))
+		assert.Equal(t, commentedString, buf.String())
+	})
+
+	t.Run("OkEdited", func(t *testing.T) {
+		t.Parallel()
+
+		document, err := hanjie.ReadDocument(strings.NewReader(commentedString))
+		assert.NoError(t, err)
+
+		second := document.Puzzles[0]
+		second.ID = "second"
+		second.Extensions = nil
+
+		document.Puzzles[0].Title = "Renamed"
+		document.Puzzles[0].Extensions["x-rating"] = 4
+		document.Puzzles = append(document.Puzzles, second)
+
+		var buf bytes.Buffer
+
+		assert.NoError(t, document.Write(&buf))
+
+		actual := buf.String()
+
+		assert.Contains(t, actual, "# Puzzles of the week\n")
+		assert.Contains(t, actual, "  # The first one\n  - id: id\n")
+		assert.Contains(t, actual, "    title: Renamed # working title\n")
+		assert.Contains(t, actual, "    # rated by hand\n    x-rating: 4\n")
+		assert.Contains(t, actual, "  - id: second\n")
+		assert.Equal(t, 1, strings.Count(actual, "# the middle row"))
+	})
+
+	t.Run("OkKeepValidWithoutID", func(t *testing.T) {
+		t.Parallel()
+
+		document, err := hanjie.ReadDocument(strings.NewReader(anonymousString), hanjie.KeepValid)
+		assert.ErrorIs(t, err, errors.ErrEmptyTitle)
+
+		var buf bytes.Buffer
+
+		assert.NoError(t, document.Write(&buf))
+
+		actual := buf.String()
+
+		assert.Contains(t, actual, "  # The valid one\n  - title: Puzzle\n")
+		assert.NotContains(t, actual, "# The invalid one")
+	})
+
+	t.Run("OkRemovedWithoutID", func(t *testing.T) {
+		t.Parallel()
+
+		document, err := hanjie.ReadDocument(strings.NewReader(anonymousString), hanjie.SkipValidation)
+		assert.NoError(t, err)
+
+		document.Puzzles = document.Puzzles[1:]
+
+		var buf bytes.Buffer
+
+		assert.NoError(t, document.Write(&buf, hanjie.SkipValidation))
+
+		actual := buf.String()
+
+		assert.Contains(t, actual, "  - title: Puzzle\n")
+		assert.NotContains(t, actual, "# The invalid one")
+	})
+
+	t.Run("OkVersion1", func(t *testing.T) {
+		t.Parallel()
+
+		document, err := hanjie.ReadDocument(strings.NewReader("# Legacy puzzles\n" + legacyString))
+		assert.NoError(t, err)
+
+		var buf bytes.Buffer
+
+		assert.NoError(t, document.Write(&buf, hanjie.WithVersion(1)))
+		assert.Equal(t, "# Legacy puzzles\n"+legacyString, buf.String())
+	})
+
+	t.Run("ErrorCauseInvalidPuzzle", func(t *testing.T) {
+		t.Parallel()
+
+		document, err := hanjie.ReadDocument(strings.NewReader(commentedString))
+		assert.NoError(t, err)
+
+		document.Puzzles[0].Title = ""
+
+		var buf bytes.Buffer
+
+		assert.ErrorIs(t, document.Write(&buf), errors.ErrEmptyTitle)
+		assert.Empty(t, buf.String())
+	})
+}
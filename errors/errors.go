@@ -1,12 +1,47 @@
 // Package errors contains errors.
 package errors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 var (
 	// ErrSyntax syntax error.
 	ErrSyntax = errors.New("syntax problems")
 
+	// ErrUnknownFormat reports that puzzle format isn't registered.
+	ErrUnknownFormat = errors.New("unknown format")
+
+	// ErrReadOnlyFormat reports that puzzle format can't be written.
+	ErrReadOnlyFormat = errors.New("format is read-only")
+
+	// ErrUnknownField reports that the key isn't a known field, see hanjie.Strict.
+	ErrUnknownField = errors.New("unknown field")
+
+	// ErrDuplicateKey reports that the key occurs in the mapping more than once, see hanjie.Strict.
+	ErrDuplicateKey = errors.New("duplicate key")
+
+	// ErrUnsupportedVersion reports that the document format version is unknown.
+	ErrUnsupportedVersion = errors.New("unsupported format version")
+
+	// ErrLimitExceeded reports that the input exceeds the limits, see hanjie.WithLimits.
+	ErrLimitExceeded = errors.New("limit exceeded")
+
+	// ErrSinglePuzzle reports that puzzle format holds exactly one puzzle.
+	ErrSinglePuzzle = errors.New("format holds exactly one puzzle")
+
+	// ErrMonochromeOnly reports that puzzle format supports monochrome puzzles only.
+	ErrMonochromeOnly = errors.New("only monochrome puzzles are supported")
+
+	// ErrUnsupportedChar reports that puzzle format can't write the color char.
+	ErrUnsupportedChar = errors.New("color char isn't supported by the format")
+
+	// ErrContradiction reports that no arrangement of the clue matches the known cells.
+	ErrContradiction = errors.New("contradiction")
+
 	// ErrEmptyAuthorName validation error, reports that puzzle author name shouldn't be empty.
 	ErrEmptyAuthorName = errors.New("author name shouldn't be empty")
 
@@ -24,6 +59,12 @@ var (
 
 	// ErrGoalDoesNotMatchTheClue validation error, reports if goal doesn't match the clue.
 	ErrGoalDoesNotMatchTheClue = errors.New("goal doesn't match the clue")
+
+	// ErrUnsolvable validation error, reports if the clue has no solution.
+	ErrUnsolvable = errors.New("clue has no solution")
+
+	// ErrMultipleSolutions validation error, reports if the clue has more than one solution.
+	ErrMultipleSolutions = errors.New("clue has multiple solutions")
 )
 
 // ValidationError puzzle validation errors batch.
@@ -31,10 +72,145 @@ type ValidationError []error
 
 // Error returns validation error message.
 func (e ValidationError) Error() string {
-	return "validation error"
+	if len(e) == 0 {
+		return "validation error"
+	}
+
+	var b strings.Builder
+
+	b.WriteString("validation error:")
+	for _, err := range e {
+		b.WriteString("\n  ")
+		b.WriteString(err.Error())
+	}
+
+	return b.String()
 }
 
 // Append error.
 func (e *ValidationError) Append(err error) {
 	*e = append(*e, err)
 }
+
+// Is reports whether any error in the batch matches target.
+func (e ValidationError) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first error in the batch that matches target.
+func (e ValidationError) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Position in the source document.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+// String returns position in file:line:column form.
+func (p Position) String() string {
+	if p.Line == 0 {
+		return p.File
+	}
+
+	pos := strconv.Itoa(p.Line)
+	if p.Column > 0 {
+		pos += ":" + strconv.Itoa(p.Column)
+	}
+
+	if p.File == "" {
+		return pos
+	}
+
+	return p.File + ":" + pos
+}
+
+// SyntaxError syntax error at the position of the source document.
+// It always matches ErrSyntax.
+type SyntaxError struct {
+	Position Position
+	Err      error
+}
+
+// Error returns syntax error message.
+func (e *SyntaxError) Error() string {
+	if pos := e.Position.String(); pos != "" {
+		return pos + ": " + e.Err.Error()
+	}
+
+	return e.Err.Error()
+}
+
+// Unwrap returns the cause.
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrSyntax.
+func (e *SyntaxError) Is(target error) bool {
+	return target == ErrSyntax
+}
+
+// PuzzleError validation error attributed to the puzzle field.
+type PuzzleError struct {
+	// Index of the puzzle in the set.
+	Index int
+	// ID of the puzzle.
+	ID string
+	// Field path, e.g. clue.rows[3][1].count.
+	Field string
+	// Rule name.
+	Rule string
+	// Position of the field in the source document, if known.
+	Position Position
+	// Err is the cause.
+	Err error
+}
+
+// Error returns puzzle error message.
+func (e *PuzzleError) Error() string {
+	var b strings.Builder
+
+	if pos := e.Position.String(); pos != "" {
+		b.WriteString(pos)
+		b.WriteString(": ")
+	}
+
+	fmt.Fprintf(&b, "puzzle #%d", e.Index)
+	if e.ID != "" {
+		fmt.Fprintf(&b, " %q", e.ID)
+	}
+
+	if e.Field != "" {
+		b.WriteString(" ")
+		b.WriteString(e.Field)
+	}
+
+	b.WriteString(": ")
+	b.WriteString(e.Err.Error())
+
+	if e.Rule != "" {
+		fmt.Fprintf(&b, " (%s)", e.Rule)
+	}
+
+	return b.String()
+}
+
+// Unwrap returns the cause.
+func (e *PuzzleError) Unwrap() error {
+	return e.Err
+}
@@ -32,3 +32,124 @@ func TestValidationError_Append(t *testing.T) {
 	assert.Len(t, actual, 2)
 	assert.Equal(t, expected, actual)
 }
+
+func TestValidationError_ErrorMultiline(t *testing.T) {
+	t.Parallel()
+
+	err := errors.ValidationError{
+		&errors.PuzzleError{Index: 3, ID: "foo", Field: "title", Rule: "title", Err: errors.ErrEmptyTitle},
+		&errors.PuzzleError{Index: 4, Field: "goal", Err: errors.ErrGoalIsIncorrect},
+	}
+
+	expected := "validation error:\n" +
+		"  puzzle #3 \"foo\" title: title shouldn't be empty (title)\n" +
+		"  puzzle #4 goal: goal is incorrect"
+
+	assert.Equal(t, expected, err.Error())
+}
+
+func TestValidationError_Is(t *testing.T) {
+	t.Parallel()
+
+	err := errors.ValidationError{
+		&errors.PuzzleError{Err: errors.ErrEmptyTitle},
+	}
+
+	assert.ErrorIs(t, err, errors.ErrEmptyTitle)
+	assert.NotErrorIs(t, err, errors.ErrGoalIsIncorrect)
+}
+
+func TestValidationError_As(t *testing.T) {
+	t.Parallel()
+
+	expected := &errors.PuzzleError{Index: 1, Err: errors.ErrEmptyTitle}
+	err := errors.ValidationError{
+		goerrors.New("foo"),
+		expected,
+	}
+
+	var actual *errors.PuzzleError
+
+	assert.ErrorAs(t, err, &actual)
+	assert.Same(t, expected, actual)
+}
+
+func TestPuzzleError_Unwrap(t *testing.T) {
+	t.Parallel()
+
+	err := &errors.PuzzleError{Err: errors.ErrEmptyTitle}
+
+	assert.ErrorIs(t, err, errors.ErrEmptyTitle)
+}
+
+func TestPosition_String(t *testing.T) {
+	t.Parallel()
+
+	testData := [...]struct {
+		name     string
+		position errors.Position
+		expected string
+	}{
+		{
+			name:     "Empty",
+			position: errors.Position{},
+			expected: "",
+		},
+		{
+			name:     "FileOnly",
+			position: errors.Position{File: "foo.yml"},
+			expected: "foo.yml",
+		},
+		{
+			name:     "Line",
+			position: errors.Position{File: "foo.yml", Line: 3},
+			expected: "foo.yml:3",
+		},
+		{
+			name:     "LineAndColumn",
+			position: errors.Position{File: "foo.yml", Line: 3, Column: 5},
+			expected: "foo.yml:3:5",
+		},
+		{
+			name:     "WithoutFile",
+			position: errors.Position{Line: 3, Column: 5},
+			expected: "3:5",
+		},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testDatum.expected, testDatum.position.String())
+		})
+	}
+}
+
+func TestSyntaxError(t *testing.T) {
+	t.Parallel()
+
+	cause := goerrors.New("foo")
+	err := &errors.SyntaxError{
+		Position: errors.Position{File: "foo.yml", Line: 3, Column: 5},
+		Err:      cause,
+	}
+
+	assert.Equal(t, "foo.yml:3:5: foo", err.Error())
+	assert.ErrorIs(t, err, errors.ErrSyntax)
+	assert.ErrorIs(t, err, cause)
+}
+
+func TestPuzzleError_ErrorWithPosition(t *testing.T) {
+	t.Parallel()
+
+	err := &errors.PuzzleError{
+		Field:    "title",
+		Position: errors.Position{File: "foo.yml", Line: 3, Column: 5},
+		Err:      errors.ErrEmptyTitle,
+	}
+
+	assert.Equal(t, "foo.yml:3:5: puzzle #0 title: title shouldn't be empty", err.Error())
+}
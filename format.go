@@ -0,0 +1,242 @@
+package hanjie
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/format/gno"
+	"github.com/alexeyco/hanjie/format/nin"
+	"github.com/alexeyco/hanjie/format/non"
+	"github.com/alexeyco/hanjie/format/olsak"
+	"github.com/alexeyco/hanjie/format/pattern"
+	"github.com/alexeyco/hanjie/format/webpbn"
+)
+
+// Built-in formats. Pattern, Olsak and Nin files have no title, so the default validator rejects their puzzles
+// with errors.ErrEmptyTitle: read them with SkipValidation, or set the title and validate the puzzles then.
+const (
+	FormatYAML    = "yaml"
+	FormatJSON    = "json"
+	FormatWebPBN  = "webpbn"
+	FormatNon     = "non"
+	FormatPattern = "pattern"
+	FormatOlsak   = "olsak"
+	FormatNin     = "nin"
+	FormatGno     = "gno"
+)
+
+// sniffLen is the number of bytes ReadAny looks at to detect the format.
+const sniffLen = 512
+
+// DetectFunc reports whether the beginning of the input looks like the format.
+type DetectFunc func(head []byte) bool
+
+// DecodeFunc decodes set of puzzles without validation.
+type DecodeFunc func(r io.Reader) (*ast.PuzzleSet, error)
+
+// EncodeFunc encodes set of puzzles without validation.
+type EncodeFunc func(w io.Writer, puzzleSet *ast.PuzzleSet) error
+
+type format struct {
+	name   string
+	detect DetectFunc
+	read   func(r io.Reader, o Options) (*ast.Collection, func(index int) *yaml.Node, error)
+	write  func(w io.Writer, collection *ast.Collection, o Options) error
+}
+
+var registry = struct {
+	sync.RWMutex
+	formats []format
+}{}
+
+func init() {
+	register(format{
+		name:  FormatYAML,
+		read:  readYAML,
+		write: writeYAML,
+	})
+
+	register(format{
+		name:   FormatJSON,
+		detect: detectJSON,
+		read:   readJSON,
+		write:  writeJSON,
+	})
+
+	RegisterFormat(FormatWebPBN, detectXML, webpbn.Read, webpbn.Write)
+	RegisterFormat(FormatNon, detectNon, decodeSingle(non.Read), encodeSingle(non.Write))
+	RegisterFormat(FormatPattern, detectPattern, decodeSingle(pattern.Read), encodeSingle(pattern.Write))
+	RegisterFormat(FormatOlsak, detectOlsak, decodeSingle(olsak.Read), encodeSingle(olsak.Write))
+	RegisterFormat(FormatNin, detectNin, decodeSingle(nin.Read), encodeSingle(nin.Write))
+	RegisterFormat(FormatGno, detectGno, decodeSingle(gno.Read), encodeSingle(gno.Write))
+}
+
+// RegisterFormat registers puzzle format to be used with WithFormat and ReadAny.
+// Detector may be nil if the format can't be sniffed, encoder may be nil if the format is read-only.
+// Registering a format with the name already in use replaces it. Collection metadata isn't passed to the format.
+// It panics if decoder is nil.
+func RegisterFormat(name string, detector DetectFunc, decoder DecodeFunc, encoder EncodeFunc) {
+	if decoder == nil {
+		panic(`hanjie: RegisterFormat decoder of format "` + name + `" is nil`)
+	}
+
+	f := format{
+		name:   name,
+		detect: detector,
+		read: func(r io.Reader, o Options) (*ast.Collection, func(int) *yaml.Node, error) {
+			puzzleSet, err := decoder(r)
+			if err != nil {
+				return nil, nil, syntaxError(o, err)
+			}
+
+			return &ast.Collection{Puzzles: *puzzleSet}, nil, nil
+		},
+	}
+
+	if encoder != nil {
+		f.write = func(w io.Writer, collection *ast.Collection, _ Options) error {
+			return encoder(w, &collection.Puzzles)
+		}
+	}
+
+	register(f)
+}
+
+func register(f format) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	for i := range registry.formats {
+		if registry.formats[i].name == f.name {
+			registry.formats[i] = f
+
+			return
+		}
+	}
+
+	registry.formats = append(registry.formats, f)
+}
+
+func lookupFormat(name string) (format, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	for _, f := range registry.formats {
+		if f.name == name {
+			return f, nil
+		}
+	}
+
+	return format{}, fmt.Errorf(`%w "%s"`, errors.ErrUnknownFormat, name)
+}
+
+// detectFormat returns the name of the first registered format that recognizes the input or yaml.
+func detectFormat(head []byte) string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	for _, f := range registry.formats {
+		if f.detect != nil && f.detect(head) {
+			return f.name
+		}
+	}
+
+	return FormatYAML
+}
+
+// ReadAny reads set of puzzles from io.Reader sniffing the format of the input.
+// If no registered format recognizes the input, it's read as YAML. WithFormat option is ignored.
+// Formats without titles need SkipValidation, see FormatPattern.
+func ReadAny(r io.Reader, options ...Option) (*ast.PuzzleSet, error) {
+	br := bufio.NewReaderSize(r, sniffLen)
+
+	head, err := br.Peek(sniffLen)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+
+	return Read(br, append(options, WithFormat(detectFormat(head)))...)
+}
+
+func detectJSON(head []byte) bool {
+	head = trimLeft(head)
+	if len(head) == 0 || head[0] != '[' && head[0] != '{' {
+		return false
+	}
+
+	next := trimLeft(head[1:])
+
+	return len(next) == 0 || bytes.IndexByte([]byte(`{}]"`), next[0]) >= 0
+}
+
+func detectXML(head []byte) bool {
+	head = trimLeft(head)
+
+	return bytes.HasPrefix(head, []byte("<")) && bytes.Contains(head, []byte("<puzzle"))
+}
+
+var nonKeyword = regexp.MustCompile(`(?m)^[ \t]*(width|height)[ \t]+\d+[ \t]*\r?$`)
+
+func detectNon(head []byte) bool {
+	return nonKeyword.Match(head)
+}
+
+var patternGameID = regexp.MustCompile(`^\s*\d+(x\d+)?:[\d./]*(,\S*)?\s*$`)
+
+func detectPattern(head []byte) bool {
+	return bytes.HasPrefix(head, []byte("SAVEFILE:41:Simon Tatham's Portable Puzzle Collection")) ||
+		patternGameID.Match(head)
+}
+
+var olsakSection = regexp.MustCompile(`(?m)^:[ \t]*(rows|columns)[ \t]*\r?$`)
+
+func detectOlsak(head []byte) bool {
+	return olsakSection.Match(head)
+}
+
+var ninSize = regexp.MustCompile(`^\s*\d+[ \t]+\d+[ \t]*\r?\n`)
+
+func detectNin(head []byte) bool {
+	return ninSize.Match(head)
+}
+
+var gnoHeading = regexp.MustCompile(`(?mi)^[ \t]*\[(description|dimensions|row clues|column clues)\][ \t]*\r?$`)
+
+func detectGno(head []byte) bool {
+	return gnoHeading.Match(head)
+}
+
+// decodeSingle adapts decoder of a single puzzle format.
+func decodeSingle(decode func(r io.Reader) (*ast.Puzzle, error)) DecodeFunc {
+	return func(r io.Reader) (*ast.PuzzleSet, error) {
+		puzzle, err := decode(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ast.PuzzleSet{*puzzle}, nil
+	}
+}
+
+// encodeSingle adapts encoder of a single puzzle format, the set should contain exactly one puzzle.
+func encodeSingle(encode func(w io.Writer, puzzle *ast.Puzzle) error) EncodeFunc {
+	return func(w io.Writer, puzzleSet *ast.PuzzleSet) error {
+		if len(*puzzleSet) != 1 {
+			return fmt.Errorf("%w, got %d", errors.ErrSinglePuzzle, len(*puzzleSet))
+		}
+
+		return encode(w, &(*puzzleSet)[0])
+	}
+}
+
+func trimLeft(b []byte) []byte {
+	return bytes.TrimLeft(bytes.TrimPrefix(b, []byte("\xef\xbb\xbf")), " \t\r\n")
+}
@@ -0,0 +1,321 @@
+// Package gno reads and writes monochrome puzzles in the INI-style .gno format of GNOME gnonograms.
+package gno
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/format/internal/lines"
+	"github.com/alexeyco/hanjie/tools"
+)
+
+// Sections are recognized by the first three letters of their headings, the way gnonograms does.
+const (
+	descriptionSection = "DES"
+	licenseSection     = "LIC"
+	dimensionsSection  = "DIM"
+	cluesSection       = "CLU"
+	rowsSection        = "ROW"
+	columnsSection     = "COL"
+	solutionSection    = "SOL"
+)
+
+// Cell states of the solution.
+const (
+	empty  = "1"
+	filled = "2"
+)
+
+type entry struct {
+	text string
+	line int
+}
+
+type section struct {
+	entries []entry
+	line    int
+}
+
+// Read a puzzle from io.Reader, the puzzle isn't validated. Unknown sections like the working grid are ignored.
+func Read(r io.Reader) (*ast.Puzzle, error) {
+	sections, end, err := readSections(r)
+	if err != nil {
+		return nil, err
+	}
+
+	puzzle := &ast.Puzzle{
+		Background: ast.BackgroundChar,
+		Colors:     ast.MonochromeColors(),
+	}
+
+	if description, ok := sections[descriptionSection]; ok {
+		readDescription(puzzle, description)
+	}
+
+	if license, ok := sections[licenseSection]; ok {
+		puzzle.Copyright = strings.TrimSpace(strings.Join(texts(license.entries), "\n"))
+	}
+
+	height, width := -1, -1
+
+	if dimensions, ok := sections[dimensionsSection]; ok {
+		if height, width, err = readDimensions(dimensions); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = readClues(puzzle, sections, height, width, end); err != nil {
+		return nil, err
+	}
+
+	if solution, ok := sections[solutionSection]; ok {
+		if puzzle.Goal, err = readSolution(solution, len(puzzle.Clue.Rows), len(puzzle.Clue.Columns)); err != nil {
+			return nil, err
+		}
+	}
+
+	return puzzle, nil
+}
+
+// Write the puzzle to io.Writer.
+func Write(w io.Writer, puzzle *ast.Puzzle) error {
+	foreground, err := tools.Foreground(*puzzle)
+	if err != nil {
+		return err
+	}
+
+	b := bufio.NewWriter(w)
+
+	author := ""
+	if puzzle.Author != nil {
+		author = puzzle.Author.Name
+	}
+
+	fmt.Fprintf(b, "[Description]\n%s\n%s\n", puzzle.Title, author)
+
+	if puzzle.Copyright != "" {
+		fmt.Fprintf(b, "[License]\n%s\n", puzzle.Copyright)
+	}
+
+	fmt.Fprintf(b, "[Dimensions]\n%d\n%d\n", len(puzzle.Clue.Rows), len(puzzle.Clue.Columns))
+
+	for _, clues := range [...]struct {
+		heading string
+		lines   []ast.Line
+	}{
+		{heading: "Row clues", lines: puzzle.Clue.Rows},
+		{heading: "Column clues", lines: puzzle.Clue.Columns},
+	} {
+		fmt.Fprintf(b, "[%s]\n", clues.heading)
+
+		for _, line := range clues.lines {
+			fmt.Fprintln(b, lines.FormatLine(line, ","))
+		}
+	}
+
+	if puzzle.Goal != nil {
+		fmt.Fprintln(b, "[Solution]")
+
+		for _, row := range *puzzle.Goal {
+			cells := make([]string, 0, len(row))
+
+			for _, ch := range row {
+				switch ch {
+				case foreground:
+					cells = append(cells, filled)
+				case puzzle.Background:
+					cells = append(cells, empty)
+				default:
+					return fmt.Errorf(`%w: unexpected goal char "%s"`, errors.ErrMonochromeOnly, string(ch))
+				}
+			}
+
+			fmt.Fprintln(b, strings.Join(cells, " "))
+		}
+	}
+
+	return b.Flush()
+}
+
+// readSections reads lines of sections by their keys, lines before the first heading aren't allowed.
+// The number of the last line of the input is returned along with the sections.
+func readSections(r io.Reader) (map[string]*section, int, error) {
+	s := lines.NewScanner(r)
+	sections := map[string]*section{}
+
+	var current *section
+
+	for s.Scan() {
+		text := strings.TrimSpace(s.Text())
+
+		if strings.HasPrefix(text, "[") {
+			if !strings.HasSuffix(text, "]") || len(text) < 5 {
+				return nil, 0, s.Errorf(`wrong section heading "%s"`, text)
+			}
+
+			key := strings.ToUpper(text[1:4])
+			if _, ok := sections[key]; ok {
+				return nil, 0, s.Errorf(`section "%s" is repeated`, text)
+			}
+
+			current = &section{line: s.Line()}
+			sections[key] = current
+
+			continue
+		}
+
+		if current == nil {
+			if text == "" {
+				continue
+			}
+
+			return nil, 0, s.Errorf(`unexpected line "%s" before the first section`, text)
+		}
+
+		current.entries = append(current.entries, entry{text: text, line: s.Line()})
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return sections, s.Line(), nil
+}
+
+// readDescription reads the name and the author, the date and other lines are ignored.
+func readDescription(puzzle *ast.Puzzle, description *section) {
+	for i, e := range description.entries {
+		switch {
+		case i == 0:
+			puzzle.Title = e.text
+		case i == 1 && e.text != "":
+			puzzle.Author = &ast.Author{Name: e.text}
+		}
+	}
+}
+
+func readDimensions(dimensions *section) (height, width int, err error) {
+	values := nonEmpty(dimensions.entries)
+	if len(values) != 2 {
+		return 0, 0, lines.Errorf(dimensions.line, "dimensions should be the number of rows and the number of columns")
+	}
+
+	sizes := [2]int{}
+	for i, e := range values {
+		if sizes[i], err = strconv.Atoi(e.text); err != nil || sizes[i] < 0 {
+			return 0, 0, lines.Errorf(e.line, `wrong dimension "%s"`, e.text)
+		}
+	}
+
+	return sizes[0], sizes[1], nil
+}
+
+// readClues reads row and column clues from their own sections, or from the single clues section
+// where row clues come first. Missing clues are reported at the end of the input.
+func readClues(puzzle *ast.Puzzle, sections map[string]*section, height, width, end int) (err error) {
+	rows, columns := sections[rowsSection], sections[columnsSection]
+
+	if clues, ok := sections[cluesSection]; ok && rows == nil && columns == nil {
+		if height < 0 {
+			return lines.Errorf(clues.line, "dimensions should precede clues")
+		}
+
+		entries := nonEmpty(clues.entries)
+		if len(entries) != height+width {
+			return lines.Errorf(clues.line, "expected %d clues, got %d", height+width, len(entries))
+		}
+
+		rows = &section{entries: entries[:height], line: clues.line}
+		columns = &section{entries: entries[height:], line: clues.line}
+	}
+
+	if rows == nil || columns == nil {
+		return lines.Errorf(end, "row and column clues are required")
+	}
+
+	if puzzle.Clue.Rows, err = readLines(rows, height, "rows"); err != nil {
+		return err
+	}
+
+	puzzle.Clue.Columns, err = readLines(columns, width, "columns")
+
+	return err
+}
+
+func readLines(clues *section, count int, name string) ([]ast.Line, error) {
+	entries := nonEmpty(clues.entries)
+	if count >= 0 && len(entries) != count {
+		return nil, lines.Errorf(clues.line, "expected %d %s, got %d", count, name, len(entries))
+	}
+
+	result := make([]ast.Line, 0, len(entries))
+
+	for _, e := range entries {
+		line, err := lines.ParseLine(e.text, ", ", ast.ForegroundChar)
+		if err != nil {
+			return nil, lines.Errorf(e.line, "%v", err)
+		}
+
+		result = append(result, line)
+	}
+
+	return result, nil
+}
+
+func readSolution(solution *section, height, width int) (*ast.Goal, error) {
+	entries := nonEmpty(solution.entries)
+	if len(entries) != height {
+		return nil, lines.Errorf(solution.line, "solution should have %d rows, got %d", height, len(entries))
+	}
+
+	goal := make(ast.Goal, 0, height)
+
+	for _, e := range entries {
+		cells := strings.Fields(e.text)
+		if len(cells) != width {
+			return nil, lines.Errorf(e.line, "solution row should have %d cells, got %d", width, len(cells))
+		}
+
+		row := make([]ast.Char, 0, width)
+
+		for _, cell := range cells {
+			switch cell {
+			case empty:
+				row = append(row, ast.BackgroundChar)
+			case filled:
+				row = append(row, ast.ForegroundChar)
+			default:
+				return nil, lines.Errorf(e.line, `unexpected solution cell "%s"`, cell)
+			}
+		}
+
+		goal = append(goal, row)
+	}
+
+	return &goal, nil
+}
+
+func nonEmpty(entries []entry) []entry {
+	result := make([]entry, 0, len(entries))
+	for _, e := range entries {
+		if e.text != "" {
+			result = append(result, e)
+		}
+	}
+
+	return result
+}
+
+func texts(entries []entry) []string {
+	result := make([]string, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, e.text)
+	}
+
+	return result
+}
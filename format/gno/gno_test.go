@@ -0,0 +1,194 @@
+package gno_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/format/gno"
+	"github.com/stretchr/testify/assert"
+)
+
+const expectedGno = `[Description]
+Puzzle
+John Doe
+[License]
+CC BY-SA
+[Dimensions]
+3
+3
+[Row clues]
+2
+1,1
+2
+[Column clues]
+2
+1,1
+2
+[Solution]
+2 2 1
+2 1 2
+1 2 2
+`
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	testData := [...]struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "Ok",
+			input: expectedGno,
+		},
+		{
+			name: "OkWithDateAndWorkingGrid",
+			input: "[Description]\nPuzzle\nJohn Doe\n2021-06-01\n\n[license]\nCC BY-SA\n\n" +
+				"[Dimensions]\n3\n3\n\n[Row clues]\n2\n1, 1\n2\n\n[Column clues]\n2\n1, 1\n2\n\n" +
+				"[Solution]\n2 2 1\n2 1 2\n1 2 2\n\n[Working grid]\n0 0 0\n0 0 0\n0 0 0\n",
+		},
+		{
+			name: "OkWithSingleCluesSection",
+			input: "[Description]\nPuzzle\nJohn Doe\n[License]\nCC BY-SA\n[Dimensions]\n3\n3\n" +
+				"[Clues]\n2\n1,1\n2\n2\n1,1\n2\n[Solution]\n2 2 1\n2 1 2\n1 2 2\n",
+		},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := gno.Read(strings.NewReader(testDatum.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, newPuzzle(), actual)
+		})
+	}
+
+	errorData := [...]struct {
+		name  string
+		input string
+		line  int
+	}{
+		{
+			name:  "ErrorCauseLineBeforeSection",
+			input: "Puzzle\n",
+			line:  1,
+		},
+		{
+			name:  "ErrorCauseWrongHeading",
+			input: "[Description\n",
+			line:  1,
+		},
+		{
+			name:  "ErrorCauseRepeatedSection",
+			input: "[Row clues]\n1\n[Rows]\n",
+			line:  3,
+		},
+		{
+			name:  "ErrorCauseWrongDimension",
+			input: "[Dimensions]\n1\nx\n",
+			line:  3,
+		},
+		{
+			name:  "ErrorCauseClueCount",
+			input: "[Dimensions]\n2\n1\n[Row clues]\n1\n[Column clues]\n1\n",
+			line:  4,
+		},
+		{
+			name:  "ErrorCauseWrongClue",
+			input: "[Row clues]\n1\n[Column clues]\n1;1\n",
+			line:  4,
+		},
+		{
+			name:  "ErrorCauseSolutionCell",
+			input: "[Row clues]\n1\n[Column clues]\n1\n[Solution]\n3\n",
+			line:  6,
+		},
+		{
+			name:  "ErrorCauseNoColumnClues",
+			input: "[Row clues]\n1\n\n",
+			line:  3,
+		},
+		{
+			name:  "ErrorCauseNoClues",
+			input: "[Description]\nPuzzle\n",
+			line:  2,
+		},
+	}
+
+	for _, testDatum := range errorData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := gno.Read(strings.NewReader(testDatum.input))
+
+			var syntaxError *errors.SyntaxError
+
+			assert.Nil(t, actual)
+			assert.ErrorIs(t, err, errors.ErrSyntax)
+			assert.ErrorAs(t, err, &syntaxError)
+			assert.Equal(t, testDatum.line, syntaxError.Position.Line)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		err := gno.Write(&buf, newPuzzle())
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedGno, buf.String())
+	})
+
+	t.Run("ErrorCauseMultiColored", func(t *testing.T) {
+		t.Parallel()
+
+		puzzle := newPuzzle()
+		puzzle.Colors[ast.Char('y')] = ast.Color{R: 255}
+
+		assert.ErrorIs(t, gno.Write(&bytes.Buffer{}, puzzle), errors.ErrMonochromeOnly)
+	})
+}
+
+func newPuzzle() *ast.Puzzle {
+	x, o := ast.ForegroundChar, ast.BackgroundChar
+
+	return &ast.Puzzle{
+		Author:     &ast.Author{Name: "John Doe"},
+		Copyright:  "CC BY-SA",
+		Title:      "Puzzle",
+		Background: o,
+		Colors:     ast.MonochromeColors(),
+		Clue: ast.Clue{
+			Columns: []ast.Line{
+				{{Color: x, Count: 2}},
+				{{Color: x, Count: 1}, {Color: x, Count: 1}},
+				{{Color: x, Count: 2}},
+			},
+			Rows: []ast.Line{
+				{{Color: x, Count: 2}},
+				{{Color: x, Count: 1}, {Color: x, Count: 1}},
+				{{Color: x, Count: 2}},
+			},
+		},
+		Goal: &ast.Goal{
+			{x, x, o},
+			{x, o, x},
+			{o, x, x},
+		},
+	}
+}
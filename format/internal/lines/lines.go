@@ -0,0 +1,122 @@
+// Package lines contains helpers shared by line-based puzzle formats.
+package lines
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+)
+
+const maxLineLength = 1 << 24
+
+// Scanner reads input line by line keeping track of the line number.
+type Scanner struct {
+	scanner *bufio.Scanner
+	line    int
+	text    string
+	back    bool
+}
+
+// NewScanner returns a new scanner that reads from r.
+func NewScanner(r io.Reader) *Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineLength)
+
+	return &Scanner{
+		scanner: scanner,
+	}
+}
+
+// Scan advances to the next line, trailing whitespace and "\r" are trimmed.
+func (s *Scanner) Scan() bool {
+	if s.back {
+		s.back = false
+
+		return true
+	}
+
+	if !s.scanner.Scan() {
+		return false
+	}
+
+	s.line++
+	s.text = strings.TrimRight(s.scanner.Text(), " \t\r")
+
+	return true
+}
+
+// Unscan makes the next Scan return the current line again.
+func (s *Scanner) Unscan() {
+	s.back = true
+}
+
+// Text returns the current line.
+func (s *Scanner) Text() string {
+	return s.text
+}
+
+// Line returns the current line number.
+func (s *Scanner) Line() int {
+	return s.line
+}
+
+// Err returns the first non-EOF error that was encountered by the scanner.
+func (s *Scanner) Err() error {
+	if err := s.scanner.Err(); err != nil {
+		return s.Errorf("%v", err)
+	}
+
+	return nil
+}
+
+// Errorf returns errors.ErrSyntax positioned at the current line.
+func (s *Scanner) Errorf(format string, args ...interface{}) error {
+	return Errorf(s.line, format, args...)
+}
+
+// Errorf returns errors.ErrSyntax positioned at the line.
+func Errorf(line int, format string, args ...interface{}) error {
+	return &errors.SyntaxError{
+		Position: errors.Position{Line: line},
+		Err:      fmt.Errorf("%w: %s", errors.ErrSyntax, fmt.Sprintf(format, args...)),
+	}
+}
+
+// ParseLine parses clue counts separated by any of separators, "0" and empty string stand for empty line.
+func ParseLine(s, separators string, color ast.Char) (ast.Line, error) {
+	line := ast.Line{}
+
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool {
+		return strings.ContainsRune(separators, r)
+	}) {
+		count, err := strconv.Atoi(field)
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf(`wrong count "%s"`, field)
+		}
+
+		if count > 0 {
+			line = append(line, ast.Item{Color: color, Count: count})
+		}
+	}
+
+	return line, nil
+}
+
+// FormatLine formats clue counts separated by separator, empty line is formatted as "0".
+func FormatLine(line ast.Line, separator string) string {
+	if len(line) == 0 {
+		return "0"
+	}
+
+	counts := make([]string, 0, len(line))
+	for _, item := range line {
+		counts = append(counts, strconv.Itoa(item.Count))
+	}
+
+	return strings.Join(counts, separator)
+}
@@ -0,0 +1,108 @@
+// Package nin reads and writes monochrome puzzles in the .nin format of Jakub Wilk's nonogram solver.
+// The first line holds the width and the height, row clues and column clues follow one per line.
+package nin
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/format/internal/lines"
+	"github.com/alexeyco/hanjie/tools"
+)
+
+// Read a puzzle from io.Reader, the puzzle isn't validated.
+func Read(r io.Reader) (*ast.Puzzle, error) {
+	s := lines.NewScanner(r)
+
+	var size []string
+	for s.Scan() {
+		if size = strings.Fields(s.Text()); len(size) > 0 {
+			break
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(size) != 2 {
+		return nil, s.Errorf(`the first line "%s" should be "<width> <height>"`, strings.Join(size, " "))
+	}
+
+	width, err := strconv.Atoi(size[0])
+	if err != nil || width < 0 {
+		return nil, s.Errorf(`wrong width "%s"`, size[0])
+	}
+
+	height, err := strconv.Atoi(size[1])
+	if err != nil || height < 0 {
+		return nil, s.Errorf(`wrong height "%s"`, size[1])
+	}
+
+	puzzle := &ast.Puzzle{
+		Background: ast.BackgroundChar,
+		Colors:     ast.MonochromeColors(),
+		Clue: ast.Clue{
+			Columns: []ast.Line{},
+			Rows:    []ast.Line{},
+		},
+	}
+
+	for _, section := range [...]struct {
+		lines *[]ast.Line
+		count int
+	}{
+		{lines: &puzzle.Clue.Rows, count: height},
+		{lines: &puzzle.Clue.Columns, count: width},
+	} {
+		for len(*section.lines) < section.count {
+			if !s.Scan() {
+				if err = s.Err(); err != nil {
+					return nil, err
+				}
+
+				return nil, s.Errorf("expected %d rows and %d columns", height, width)
+			}
+
+			line, err := lines.ParseLine(s.Text(), " \t", ast.ForegroundChar)
+			if err != nil {
+				return nil, s.Errorf("%v", err)
+			}
+
+			*section.lines = append(*section.lines, line)
+		}
+	}
+
+	for s.Scan() {
+		if strings.TrimSpace(s.Text()) != "" {
+			return nil, s.Errorf("unexpected line after %d rows and %d columns", height, width)
+		}
+	}
+
+	if err = s.Err(); err != nil {
+		return nil, err
+	}
+
+	return puzzle, nil
+}
+
+// Write the puzzle to io.Writer, the goal isn't written.
+func Write(w io.Writer, puzzle *ast.Puzzle) error {
+	if _, err := tools.Foreground(*puzzle); err != nil {
+		return err
+	}
+
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintf(b, "%d %d\n", len(puzzle.Clue.Columns), len(puzzle.Clue.Rows))
+
+	for _, line := range append(append([]ast.Line{}, puzzle.Clue.Rows...), puzzle.Clue.Columns...) {
+		fmt.Fprintln(b, lines.FormatLine(line, " "))
+	}
+
+	return b.Flush()
+}
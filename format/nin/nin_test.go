@@ -0,0 +1,153 @@
+package nin_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/format/nin"
+	"github.com/stretchr/testify/assert"
+)
+
+const expectedNin = `3 3
+2
+1 1
+2
+2
+1 1
+2
+`
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := nin.Read(strings.NewReader("\n3  3\r\n2\n1\t1\n2\n2\n1 1\n2\n\n"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, newPuzzle(), actual)
+	})
+
+	t.Run("OkEmptyLines", func(t *testing.T) {
+		t.Parallel()
+
+		expected := &ast.Puzzle{
+			Background: ast.BackgroundChar,
+			Colors:     ast.MonochromeColors(),
+			Clue:       ast.Clue{Columns: []ast.Line{{}}, Rows: []ast.Line{{}, {}}},
+		}
+
+		actual, err := nin.Read(strings.NewReader("1 2\n\n0\n0\n"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	testData := [...]struct {
+		name  string
+		input string
+		line  int
+	}{
+		{
+			name:  "ErrorCauseWrongSize",
+			input: "3\n",
+			line:  1,
+		},
+		{
+			name:  "ErrorCauseWrongWidth",
+			input: "a 2\n",
+			line:  1,
+		},
+		{
+			name:  "ErrorCauseWrongCount",
+			input: "1 1\n1\nx\n",
+			line:  3,
+		},
+		{
+			name:  "ErrorCauseTooFewLines",
+			input: "1 1\n1\n",
+			line:  2,
+		},
+		{
+			name:  "ErrorCauseHugeWidth",
+			input: "99999999999 1\n1\n",
+			line:  2,
+		},
+		{
+			name:  "ErrorCauseHugeHeight",
+			input: "1 99999999999\n1\n",
+			line:  2,
+		},
+		{
+			name:  "ErrorCauseTooManyLines",
+			input: "1 1\n1\n1\n1\n",
+			line:  4,
+		},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := nin.Read(strings.NewReader(testDatum.input))
+
+			var syntaxError *errors.SyntaxError
+
+			assert.Nil(t, actual)
+			assert.ErrorIs(t, err, errors.ErrSyntax)
+			assert.ErrorAs(t, err, &syntaxError)
+			assert.Equal(t, testDatum.line, syntaxError.Position.Line)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		err := nin.Write(&buf, newPuzzle())
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedNin, buf.String())
+	})
+
+	t.Run("ErrorCauseMultiColored", func(t *testing.T) {
+		t.Parallel()
+
+		puzzle := newPuzzle()
+		puzzle.Colors[ast.Char('y')] = ast.Color{R: 255}
+
+		assert.ErrorIs(t, nin.Write(&bytes.Buffer{}, puzzle), errors.ErrMonochromeOnly)
+	})
+}
+
+func newPuzzle() *ast.Puzzle {
+	x := ast.ForegroundChar
+
+	return &ast.Puzzle{
+		Background: ast.BackgroundChar,
+		Colors:     ast.MonochromeColors(),
+		Clue: ast.Clue{
+			Columns: []ast.Line{
+				{{Color: x, Count: 2}},
+				{{Color: x, Count: 1}, {Color: x, Count: 1}},
+				{{Color: x, Count: 2}},
+			},
+			Rows: []ast.Line{
+				{{Color: x, Count: 2}},
+				{{Color: x, Count: 1}, {Color: x, Count: 1}},
+				{{Color: x, Count: 2}},
+			},
+		},
+	}
+}
@@ -0,0 +1,233 @@
+// Package non reads and writes monochrome puzzles in Steve Simpson's .non format.
+package non
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/format/internal/lines"
+	"github.com/alexeyco/hanjie/tools"
+)
+
+const (
+	empty  = '0'
+	filled = '1'
+)
+
+// Read a puzzle from io.Reader. Unknown keywords are ignored, the puzzle isn't validated.
+func Read(r io.Reader) (*ast.Puzzle, error) {
+	s := lines.NewScanner(r)
+
+	puzzle := &ast.Puzzle{
+		Background: ast.BackgroundChar,
+		Colors:     ast.MonochromeColors(),
+	}
+
+	width, height := -1, -1
+	goal, goalLine := "", 0
+
+	for s.Scan() {
+		key, value := splitKeyword(s.Text())
+
+		var err error
+
+		switch key {
+		case "":
+			continue
+		case "title":
+			puzzle.Title = unquote(value)
+		case "by":
+			puzzle.Author = &ast.Author{Name: unquote(value)}
+		case "copyright":
+			puzzle.Copyright = unquote(value)
+		case "width":
+			width, err = parseSize(s, value)
+		case "height":
+			height, err = parseSize(s, value)
+		case "rows":
+			puzzle.Clue.Rows, err = readLines(s, height)
+		case "columns":
+			puzzle.Clue.Columns, err = readLines(s, width)
+		case "goal":
+			goal, goalLine = unquote(value), s.Line()
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	if width >= 0 && len(puzzle.Clue.Columns) != width {
+		return nil, s.Errorf("width is %d, but %d columns are given", width, len(puzzle.Clue.Columns))
+	}
+
+	if height >= 0 && len(puzzle.Clue.Rows) != height {
+		return nil, s.Errorf("height is %d, but %d rows are given", height, len(puzzle.Clue.Rows))
+	}
+
+	if goalLine > 0 {
+		g, err := parseGoal(goal, len(puzzle.Clue.Columns), len(puzzle.Clue.Rows))
+		if err != nil {
+			return nil, &errors.SyntaxError{
+				Position: errors.Position{Line: goalLine},
+				Err:      err,
+			}
+		}
+
+		puzzle.Goal = g
+	}
+
+	return puzzle, nil
+}
+
+// Write the puzzle to io.Writer.
+func Write(w io.Writer, puzzle *ast.Puzzle) error {
+	foreground, err := tools.Foreground(*puzzle)
+	if err != nil {
+		return err
+	}
+
+	b := bufio.NewWriter(w)
+
+	if puzzle.Title != "" {
+		fmt.Fprintf(b, "title %s\n", strconv.Quote(puzzle.Title))
+	}
+
+	if puzzle.Author != nil && puzzle.Author.Name != "" {
+		fmt.Fprintf(b, "by %s\n", strconv.Quote(puzzle.Author.Name))
+	}
+
+	if puzzle.Copyright != "" {
+		fmt.Fprintf(b, "copyright %s\n", strconv.Quote(puzzle.Copyright))
+	}
+
+	fmt.Fprintf(b, "width %d\nheight %d\n", len(puzzle.Clue.Columns), len(puzzle.Clue.Rows))
+
+	for _, section := range [...]struct {
+		name  string
+		lines []ast.Line
+	}{
+		{name: "rows", lines: puzzle.Clue.Rows},
+		{name: "columns", lines: puzzle.Clue.Columns},
+	} {
+		fmt.Fprintf(b, "\n%s\n", section.name)
+
+		for _, line := range section.lines {
+			fmt.Fprintln(b, lines.FormatLine(line, ","))
+		}
+	}
+
+	if puzzle.Goal != nil {
+		var goal strings.Builder
+
+		for _, row := range *puzzle.Goal {
+			for _, ch := range row {
+				switch ch {
+				case foreground:
+					goal.WriteByte(filled)
+				case puzzle.Background:
+					goal.WriteByte(empty)
+				default:
+					return fmt.Errorf(`%w: unexpected goal char "%s"`, errors.ErrMonochromeOnly, string(ch))
+				}
+			}
+		}
+
+		fmt.Fprintf(b, "\ngoal %s\n", strconv.Quote(goal.String()))
+	}
+
+	return b.Flush()
+}
+
+func splitKeyword(text string) (key, value string) {
+	text = strings.TrimSpace(text)
+
+	i := strings.IndexAny(text, " \t")
+	if i < 0 {
+		return text, ""
+	}
+
+	return text[:i], strings.TrimSpace(text[i+1:])
+}
+
+func unquote(s string) string {
+	if u, err := strconv.Unquote(s); err == nil {
+		return u
+	}
+
+	return strings.Trim(s, `"`)
+}
+
+func parseSize(s *lines.Scanner, value string) (int, error) {
+	size, err := strconv.Atoi(value)
+	if err != nil || size < 0 {
+		return 0, s.Errorf(`wrong size "%s"`, value)
+	}
+
+	return size, nil
+}
+
+// readLines reads n clue lines, if n is unknown lines are read up to an empty line.
+func readLines(s *lines.Scanner, n int) ([]ast.Line, error) {
+	result := []ast.Line{}
+
+	for n < 0 || len(result) < n {
+		if !s.Scan() {
+			break
+		}
+
+		text := strings.TrimSpace(s.Text())
+		if n < 0 && text == "" {
+			break
+		}
+
+		if text != "" && (text[0] < '0' || text[0] > '9') {
+			s.Unscan()
+
+			break
+		}
+
+		line, err := lines.ParseLine(text, ", ", ast.ForegroundChar)
+		if err != nil {
+			return nil, s.Errorf("%v", err)
+		}
+
+		result = append(result, line)
+	}
+
+	return result, nil
+}
+
+func parseGoal(goal string, width, height int) (*ast.Goal, error) {
+	if width == 0 && len(goal) > 0 || width > 0 && (len(goal)%width != 0 || len(goal)/width != height) {
+		return nil, fmt.Errorf("%w: goal should have %dx%d cells, got %d", errors.ErrSyntax, width, height, len(goal))
+	}
+
+	g := make(ast.Goal, height)
+
+	for r := range g {
+		g[r] = make([]ast.Char, width)
+
+		for c := range g[r] {
+			switch goal[r*width+c] {
+			case empty:
+				g[r][c] = ast.BackgroundChar
+			case filled:
+				g[r][c] = ast.ForegroundChar
+			default:
+				return nil, fmt.Errorf(`%w: unexpected goal char "%c"`, errors.ErrSyntax, goal[r*width+c])
+			}
+		}
+	}
+
+	return &g, nil
+}
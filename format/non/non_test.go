@@ -0,0 +1,175 @@
+package non_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/format/non"
+	"github.com/stretchr/testify/assert"
+)
+
+const expectedNon = `title "Puzzle"
+by "John Doe"
+copyright "© John Doe"
+width 3
+height 3
+
+rows
+2
+1,1
+2
+
+columns
+2
+1,1
+2
+
+goal "110101011"
+`
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := non.Read(strings.NewReader(expectedNon))
+
+		assert.NoError(t, err)
+		assert.Equal(t, newPuzzle(), actual)
+	})
+
+	t.Run("OkWithoutSizeAndGoal", func(t *testing.T) {
+		t.Parallel()
+
+		input := "catalogue \"#1\"\ntitle Puzzle\r\nrows\n1\n\ncolumns\n1\n"
+
+		expected := &ast.Puzzle{
+			Title:      "Puzzle",
+			Background: ast.BackgroundChar,
+			Colors:     ast.MonochromeColors(),
+			Clue: ast.Clue{
+				Columns: []ast.Line{{{Color: ast.ForegroundChar, Count: 1}}},
+				Rows:    []ast.Line{{{Color: ast.ForegroundChar, Count: 1}}},
+			},
+		}
+
+		actual, err := non.Read(strings.NewReader(input))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	testData := [...]struct {
+		name  string
+		input string
+		line  int
+	}{
+		{
+			name:  "ErrorCauseWrongSize",
+			input: "width x\n",
+			line:  1,
+		},
+		{
+			name:  "ErrorCauseWrongCount",
+			input: "width 1\nheight 1\nrows\n1,a\n",
+			line:  4,
+		},
+		{
+			name:  "ErrorCauseRowsMismatch",
+			input: "width 0\nheight 2\nrows\n1\ncolumns\n",
+			line:  5,
+		},
+		{
+			name:  "ErrorCauseGoalLength",
+			input: "width 1\nheight 1\nrows\n1\ncolumns\n1\ngoal 10\n",
+			line:  7,
+		},
+		{
+			name:  "ErrorCauseGoalSizeOverflow",
+			input: "width 4294967296\nheight 4294967296\ngoal \"\"\n",
+			line:  3,
+		},
+		{
+			name:  "ErrorCauseGoalChar",
+			input: "width 1\nheight 1\nrows\n1\ncolumns\n1\ngoal \"x\"\n",
+			line:  7,
+		},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := non.Read(strings.NewReader(testDatum.input))
+
+			var syntaxError *errors.SyntaxError
+
+			assert.Nil(t, actual)
+			assert.ErrorIs(t, err, errors.ErrSyntax)
+			assert.ErrorAs(t, err, &syntaxError)
+			assert.Equal(t, testDatum.line, syntaxError.Position.Line)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		err := non.Write(&buf, newPuzzle())
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedNon, buf.String())
+	})
+
+	t.Run("ErrorCauseMultiColored", func(t *testing.T) {
+		t.Parallel()
+
+		puzzle := newPuzzle()
+		puzzle.Colors[ast.Char('y')] = ast.Color{R: 255}
+
+		err := non.Write(&bytes.Buffer{}, puzzle)
+
+		assert.ErrorIs(t, err, errors.ErrMonochromeOnly)
+	})
+}
+
+func newPuzzle() *ast.Puzzle {
+	x := ast.ForegroundChar
+	o := ast.BackgroundChar
+
+	return &ast.Puzzle{
+		Author:     &ast.Author{Name: "John Doe"},
+		Copyright:  "© John Doe",
+		Title:      "Puzzle",
+		Background: o,
+		Colors:     ast.MonochromeColors(),
+		Clue: ast.Clue{
+			Columns: []ast.Line{
+				{{Color: x, Count: 2}},
+				{{Color: x, Count: 1}, {Color: x, Count: 1}},
+				{{Color: x, Count: 2}},
+			},
+			Rows: []ast.Line{
+				{{Color: x, Count: 2}},
+				{{Color: x, Count: 1}, {Color: x, Count: 1}},
+				{{Color: x, Count: 2}},
+			},
+		},
+		Goal: &ast.Goal{
+			{x, x, o},
+			{x, o, x},
+			{o, x, x},
+		},
+	}
+}
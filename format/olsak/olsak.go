@@ -0,0 +1,269 @@
+// Package olsak reads and writes puzzles in the .g format of Mirek Olšák's nonogram solver.
+// Monochrome puzzles are lists of counts, color puzzles start with "#d" and a table of colors,
+// and every count is followed by the char of its color.
+package olsak
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/format/internal/lines"
+)
+
+const (
+	colorHeader    = "#d"
+	rowsSection    = "rows"
+	columnsSection = "columns"
+)
+
+// Read a puzzle from io.Reader, the puzzle isn't validated.
+// Counts without color are of the first color after the background one.
+func Read(r io.Reader) (*ast.Puzzle, error) {
+	s := lines.NewScanner(r)
+
+	puzzle := &ast.Puzzle{
+		Background: ast.BackgroundChar,
+		Colors:     ast.MonochromeColors(),
+	}
+
+	var (
+		colored  bool
+		section  *[]ast.Line
+		defaults = map[int]ast.Char{1: ast.ForegroundChar}
+	)
+
+	for s.Scan() {
+		text := strings.TrimSpace(s.Text())
+
+		switch {
+		case text == "":
+			continue
+		case text == colorHeader && section == nil && !colored:
+			colored = true
+			puzzle.Colors = ast.Colors{}
+			delete(defaults, 1)
+		case text[0] == '#':
+			continue
+		case text[0] == ':':
+			switch name := strings.TrimSpace(text[1:]); name {
+			case rowsSection:
+				section = &puzzle.Clue.Rows
+			case columnsSection:
+				section = &puzzle.Clue.Columns
+			default:
+				return nil, s.Errorf(`unknown section "%s"`, name)
+			}
+
+			*section = []ast.Line{}
+		case section == nil && colored:
+			index, ch, color, err := parseColor(text)
+			if err != nil {
+				return nil, s.Errorf("%v", err)
+			}
+
+			if _, ok := puzzle.Colors[ch]; ok {
+				return nil, s.Errorf(`color "%s" is defined twice`, string(ch))
+			}
+
+			puzzle.Colors[ch] = color
+			defaults[index] = ch
+		case section == nil:
+			return nil, s.Errorf(`unexpected line "%s"`, text)
+		default:
+			background := puzzle.Background
+			if colored {
+				background = defaults[0]
+			}
+
+			line, err := parseLine(text, puzzle.Colors, background, defaults[1])
+			if err != nil {
+				return nil, s.Errorf("%v", err)
+			}
+
+			*section = append(*section, line)
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	if colored {
+		background, ok := defaults[0]
+		if !ok {
+			return nil, s.Errorf("background color 0 is not defined")
+		}
+
+		puzzle.Background = background
+	}
+
+	if puzzle.Clue.Rows == nil || puzzle.Clue.Columns == nil {
+		return nil, s.Errorf(`both "%s" and "%s" sections are required`, rowsSection, columnsSection)
+	}
+
+	return puzzle, nil
+}
+
+// Write the puzzle to io.Writer. Puzzles with the default monochrome colors are written without the color table.
+// The goal isn't written.
+func Write(w io.Writer, puzzle *ast.Puzzle) error {
+	colored := !isMonochrome(puzzle)
+	b := bufio.NewWriter(w)
+
+	if colored {
+		fmt.Fprintln(b, colorHeader)
+
+		for i, ch := range colorOrder(puzzle) {
+			if unicode.IsDigit(rune(ch)) || unicode.IsSpace(rune(ch)) {
+				return fmt.Errorf(`%w: "%s" should be neither a digit nor a space`, errors.ErrUnsupportedChar, string(ch))
+			}
+
+			c := puzzle.Colors[ch]
+			fmt.Fprintf(b, "%4d:   %s  #%02X%02X%02X   %s\n", i, string(ch), c.R, c.G, c.B, colorName(i, c))
+		}
+	}
+
+	for _, section := range [...]struct {
+		name  string
+		lines []ast.Line
+	}{
+		{name: rowsSection, lines: puzzle.Clue.Rows},
+		{name: columnsSection, lines: puzzle.Clue.Columns},
+	} {
+		fmt.Fprintf(b, ": %s\n", section.name)
+
+		for _, line := range section.lines {
+			fmt.Fprintln(b, formatLine(line, colored))
+		}
+	}
+
+	return b.Flush()
+}
+
+// parseColor parses the color definition like "1:   X  #000000   black".
+func parseColor(text string) (index int, ch ast.Char, color ast.Color, err error) {
+	i := strings.IndexByte(text, ':')
+	if i < 0 {
+		return 0, 0, color, fmt.Errorf(`wrong color definition "%s"`, text)
+	}
+
+	if index, err = strconv.Atoi(strings.TrimSpace(text[:i])); err != nil || index < 0 {
+		return 0, 0, color, fmt.Errorf(`wrong color index "%s"`, strings.TrimSpace(text[:i]))
+	}
+
+	fields := strings.Fields(text[i+1:])
+	if len(fields) < 2 {
+		return 0, 0, color, fmt.Errorf(`color definition "%s" should have a char and a hex value`, text)
+	}
+
+	if err = ch.UnmarshalText([]byte(fields[0])); err != nil {
+		return 0, 0, color, err
+	}
+
+	if err = color.UnmarshalText([]byte(fields[1])); err != nil {
+		return 0, 0, color, err
+	}
+
+	return index, ch, color, nil
+}
+
+// parseLine parses counts like "2a 1b", counts without color char are of the default color.
+func parseLine(text string, colors ast.Colors, background, defaultChar ast.Char) (ast.Line, error) {
+	line := ast.Line{}
+
+	for _, token := range strings.Fields(text) {
+		digits := strings.IndexFunc(token, func(r rune) bool {
+			return r < '0' || r > '9'
+		})
+		if digits < 0 {
+			digits = len(token)
+		}
+
+		count, err := strconv.Atoi(token[:digits])
+		if err != nil {
+			return nil, fmt.Errorf(`wrong count "%s"`, token)
+		}
+
+		ch := defaultChar
+		if suffix := token[digits:]; suffix != "" {
+			r, size := utf8.DecodeRuneInString(suffix)
+			if size != len(suffix) {
+				return nil, fmt.Errorf(`wrong count "%s"`, token)
+			}
+
+			ch = ast.Char(r)
+		}
+
+		if _, ok := colors[ch]; !ok || ch == 0 || ch == background {
+			return nil, fmt.Errorf(`unknown color of count "%s"`, token)
+		}
+
+		if count > 0 {
+			line = append(line, ast.Item{Color: ch, Count: count})
+		}
+	}
+
+	return line, nil
+}
+
+func formatLine(line ast.Line, colored bool) string {
+	if !colored {
+		return lines.FormatLine(line, " ")
+	}
+
+	if len(line) == 0 {
+		return "0"
+	}
+
+	tokens := make([]string, 0, len(line))
+	for _, item := range line {
+		tokens = append(tokens, strconv.Itoa(item.Count)+string(item.Color))
+	}
+
+	return strings.Join(tokens, " ")
+}
+
+func isMonochrome(puzzle *ast.Puzzle) bool {
+	colors := ast.MonochromeColors()
+	if puzzle.Background != ast.BackgroundChar || len(puzzle.Colors) != len(colors) {
+		return false
+	}
+
+	for ch, c := range colors {
+		if puzzle.Colors[ch] != c {
+			return false
+		}
+	}
+
+	return true
+}
+
+// colorOrder returns the background char followed by other chars in ascending order.
+func colorOrder(puzzle *ast.Puzzle) []ast.Char {
+	chars := []ast.Char{puzzle.Background}
+	for _, ch := range puzzle.Colors.Chars() {
+		if ch != puzzle.Background {
+			chars = append(chars, ch)
+		}
+	}
+
+	return chars
+}
+
+func colorName(index int, c ast.Color) string {
+	switch c {
+	case ast.Color{R: 255, G: 255, B: 255}:
+		return "white"
+	case ast.Color{}:
+		return "black"
+	}
+
+	return "color" + strconv.Itoa(index)
+}
@@ -0,0 +1,232 @@
+package olsak_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/format/olsak"
+	"github.com/stretchr/testify/assert"
+)
+
+const expectedMonochrome = `: rows
+2
+1 1
+2
+: columns
+2
+1 1
+2
+`
+
+const expectedColored = `#d
+   0:   .  #FFFFFF   white
+   1:   X  #000000   black
+   2:   r  #FF0000   color2
+: rows
+2X 1r
+1r
+: columns
+1X
+1X
+2r
+`
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	testData := [...]struct {
+		name     string
+		input    string
+		expected *ast.Puzzle
+	}{
+		{
+			name:     "OkMonochrome",
+			input:    "# comment\n" + expectedMonochrome,
+			expected: newMonochromePuzzle(),
+		},
+		{
+			name:     "OkColored",
+			input:    expectedColored,
+			expected: newColoredPuzzle(),
+		},
+		{
+			name:     "OkColoredWithDefaultColor",
+			input:    "#d\n0: . #fff\n1: X #000\n2: r #f00\n\n: rows\n2 1r\n1r\n: columns\n1\n1X\n2r\n",
+			expected: newColoredPuzzle(),
+		},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := olsak.Read(strings.NewReader(testDatum.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, testDatum.expected, actual)
+		})
+	}
+
+	errorData := [...]struct {
+		name  string
+		input string
+		line  int
+	}{
+		{
+			name:  "ErrorCauseUnknownSection",
+			input: ": foo\n",
+			line:  1,
+		},
+		{
+			name:  "ErrorCauseWrongColor",
+			input: "#d\n0: . white\n",
+			line:  2,
+		},
+		{
+			name:  "ErrorCauseDuplicateColor",
+			input: "#d\n0: . #fff\n1: . #000\n",
+			line:  3,
+		},
+		{
+			name:  "ErrorCauseUnknownColor",
+			input: "#d\n0: . #fff\n1: X #000\n: rows\n1X 1r\n",
+			line:  5,
+		},
+		{
+			name:  "ErrorCauseBackgroundCount",
+			input: "#d\n0: . #fff\n1: X #000\n: rows\n1.\n",
+			line:  5,
+		},
+		{
+			name:  "ErrorCauseWrongCount",
+			input: ": rows\n1 a\n",
+			line:  2,
+		},
+		{
+			name:  "ErrorCauseNoColumns",
+			input: ": rows\n1\n",
+			line:  2,
+		},
+	}
+
+	for _, testDatum := range errorData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := olsak.Read(strings.NewReader(testDatum.input))
+
+			var syntaxError *errors.SyntaxError
+
+			assert.Nil(t, actual)
+			assert.ErrorIs(t, err, errors.ErrSyntax)
+			assert.ErrorAs(t, err, &syntaxError)
+			assert.Equal(t, testDatum.line, syntaxError.Position.Line)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	testData := [...]struct {
+		name     string
+		puzzle   *ast.Puzzle
+		expected string
+	}{
+		{
+			name:     "Monochrome",
+			puzzle:   newMonochromePuzzle(),
+			expected: expectedMonochrome,
+		},
+		{
+			name:     "Colored",
+			puzzle:   newColoredPuzzle(),
+			expected: expectedColored,
+		},
+		{
+			name: "EmptyLines",
+			puzzle: &ast.Puzzle{
+				Background: ast.BackgroundChar,
+				Colors:     ast.MonochromeColors(),
+				Clue:       ast.Clue{Columns: []ast.Line{{}}, Rows: []ast.Line{{}}},
+			},
+			expected: ": rows\n0\n: columns\n0\n",
+		},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			err := olsak.Write(&buf, testDatum.puzzle)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testDatum.expected, buf.String())
+		})
+	}
+
+	t.Run("ErrorCauseDigitChar", func(t *testing.T) {
+		t.Parallel()
+
+		puzzle := newColoredPuzzle()
+		puzzle.Colors[ast.Char('1')] = ast.Color{G: 255}
+
+		assert.ErrorIs(t, olsak.Write(&bytes.Buffer{}, puzzle), errors.ErrUnsupportedChar)
+	})
+}
+
+func newMonochromePuzzle() *ast.Puzzle {
+	x := ast.ForegroundChar
+
+	return &ast.Puzzle{
+		Background: ast.BackgroundChar,
+		Colors:     ast.MonochromeColors(),
+		Clue: ast.Clue{
+			Columns: []ast.Line{
+				{{Color: x, Count: 2}},
+				{{Color: x, Count: 1}, {Color: x, Count: 1}},
+				{{Color: x, Count: 2}},
+			},
+			Rows: []ast.Line{
+				{{Color: x, Count: 2}},
+				{{Color: x, Count: 1}, {Color: x, Count: 1}},
+				{{Color: x, Count: 2}},
+			},
+		},
+	}
+}
+
+func newColoredPuzzle() *ast.Puzzle {
+	x, r := ast.Char('X'), ast.Char('r')
+
+	return &ast.Puzzle{
+		Background: ast.Char('.'),
+		Colors: ast.Colors{
+			ast.Char('.'): ast.Color{R: 255, G: 255, B: 255},
+			x:             ast.Color{},
+			r:             ast.Color{R: 255},
+		},
+		Clue: ast.Clue{
+			Columns: []ast.Line{
+				{{Color: x, Count: 1}},
+				{{Color: x, Count: 1}},
+				{{Color: r, Count: 2}},
+			},
+			Rows: []ast.Line{
+				{{Color: x, Count: 2}, {Color: r, Count: 1}},
+				{{Color: r, Count: 1}},
+			},
+		},
+	}
+}
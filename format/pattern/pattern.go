@@ -0,0 +1,148 @@
+// Package pattern reads and writes monochrome puzzles of the Pattern game from Simon Tatham's Portable Puzzle
+// Collection, see https://www.chiark.greenend.org.uk/~sgtatham/puzzles/js/pattern.html.
+package pattern
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/format/internal/lines"
+	"github.com/alexeyco/hanjie/tools"
+)
+
+// Unknown is the char of the progress cells the player hasn't marked yet.
+const Unknown = ast.Char('?')
+
+// ParseGameID parses the game ID like "3x2:2/1/1.1/1/3", column clues come first.
+// Trailing description parts after "," are ignored.
+func ParseGameID(id string) (*ast.Puzzle, error) {
+	params, desc, ok := cut(strings.TrimSpace(id), ":")
+	if !ok {
+		return nil, fmt.Errorf(`%w: game ID "%s" should look like "WxH:clues"`, errors.ErrSyntax, id)
+	}
+
+	width, height, err := parseParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseDesc(desc, width, height)
+}
+
+// GameID returns the game ID of the puzzle.
+func GameID(puzzle *ast.Puzzle) (string, error) {
+	if _, err := tools.Foreground(*puzzle); err != nil {
+		return "", err
+	}
+
+	return formatParams(puzzle) + ":" + formatDesc(puzzle), nil
+}
+
+// Read a puzzle from io.Reader, the input is either a game ID or a save file.
+// Progress of the save file is dropped, use ReadSave to keep it.
+func Read(r io.Reader) (*ast.Puzzle, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(data, []byte(saveFileKey)) {
+		return ParseGameID(string(data))
+	}
+
+	save, err := ReadSave(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return save.Puzzle, nil
+}
+
+// Write the puzzle to io.Writer as a save file without progress.
+func Write(w io.Writer, puzzle *ast.Puzzle) error {
+	return WriteSave(w, &Save{Puzzle: puzzle})
+}
+
+func parseParams(params string) (width, height int, err error) {
+	w, h, ok := cut(params, "x")
+	if !ok {
+		h = w
+	}
+
+	if width, err = strconv.Atoi(w); err != nil || width <= 0 {
+		return 0, 0, fmt.Errorf(`%w: wrong width "%s"`, errors.ErrSyntax, w)
+	}
+
+	if height, err = strconv.Atoi(h); err != nil || height <= 0 {
+		return 0, 0, fmt.Errorf(`%w: wrong height "%s"`, errors.ErrSyntax, h)
+	}
+
+	return width, height, nil
+}
+
+func formatParams(puzzle *ast.Puzzle) string {
+	return fmt.Sprintf("%dx%d", len(puzzle.Clue.Columns), len(puzzle.Clue.Rows))
+}
+
+func parseDesc(desc string, width, height int) (*ast.Puzzle, error) {
+	if i := strings.IndexByte(desc, ','); i >= 0 {
+		desc = desc[:i]
+	}
+
+	clues := strings.Split(desc, "/")
+	if len(clues) != width+height {
+		return nil, fmt.Errorf("%w: expected %d clues, got %d", errors.ErrSyntax, width+height, len(clues))
+	}
+
+	puzzle := &ast.Puzzle{
+		Background: ast.BackgroundChar,
+		Colors:     ast.MonochromeColors(),
+		Clue: ast.Clue{
+			Columns: make([]ast.Line, width),
+			Rows:    make([]ast.Line, height),
+		},
+	}
+
+	for i, clue := range clues {
+		line, err := lines.ParseLine(clue, ".", ast.ForegroundChar)
+		if err != nil {
+			return nil, fmt.Errorf("%w: clue #%d: %v", errors.ErrSyntax, i, err)
+		}
+
+		if i < width {
+			puzzle.Clue.Columns[i] = line
+		} else {
+			puzzle.Clue.Rows[i-width] = line
+		}
+	}
+
+	return puzzle, nil
+}
+
+func formatDesc(puzzle *ast.Puzzle) string {
+	clues := make([]string, 0, len(puzzle.Clue.Columns)+len(puzzle.Clue.Rows))
+
+	for _, line := range append(append([]ast.Line{}, puzzle.Clue.Columns...), puzzle.Clue.Rows...) {
+		clue := ""
+		if len(line) > 0 {
+			clue = lines.FormatLine(line, ".")
+		}
+
+		clues = append(clues, clue)
+	}
+
+	return strings.Join(clues, "/")
+}
+
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+
+	return s, "", false
+}
@@ -0,0 +1,172 @@
+package pattern_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/format/pattern"
+	"github.com/stretchr/testify/assert"
+)
+
+const expectedGameID = "3x3:2/1.1/2/2/1.1/2"
+
+func TestParseGameID(t *testing.T) {
+	t.Parallel()
+
+	testData := [...]struct {
+		name string
+		id   string
+	}{
+		{
+			name: "Ok",
+			id:   expectedGameID,
+		},
+		{
+			name: "OkSquareWithZeroAndDescriptionSuffix",
+			id:   " 3:2/1.1/0.2/2/1.1/2,a2 \n",
+		},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := pattern.ParseGameID(testDatum.id)
+
+			assert.NoError(t, err)
+			assert.Equal(t, newPuzzle(), actual)
+		})
+	}
+
+	errorData := [...]struct {
+		name string
+		id   string
+	}{
+		{
+			name: "ErrorCauseNoParams",
+			id:   "2/1.1//2/1.1/1",
+		},
+		{
+			name: "ErrorCauseWrongWidth",
+			id:   "ax3:2/1.1//2/1.1/1",
+		},
+		{
+			name: "ErrorCauseWrongHeight",
+			id:   "3x0:2/1.1/",
+		},
+		{
+			name: "ErrorCauseWrongClueCount",
+			id:   "3x3:2/1.1//2/1.1",
+		},
+		{
+			name: "ErrorCauseWrongClue",
+			id:   "3x3:2/1.1//2/1-1/1",
+		},
+	}
+
+	for _, testDatum := range errorData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := pattern.ParseGameID(testDatum.id)
+
+			assert.Nil(t, actual)
+			assert.ErrorIs(t, err, errors.ErrSyntax)
+		})
+	}
+}
+
+func TestGameID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := pattern.GameID(newPuzzle())
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedGameID, actual)
+	})
+
+	t.Run("ErrorCauseMultiColored", func(t *testing.T) {
+		t.Parallel()
+
+		puzzle := newPuzzle()
+		puzzle.Colors[ast.Char('y')] = ast.Color{R: 255}
+
+		actual, err := pattern.GameID(puzzle)
+
+		assert.Empty(t, actual)
+		assert.ErrorIs(t, err, errors.ErrMonochromeOnly)
+	})
+}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	testData := [...]struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "GameID",
+			input: expectedGameID + "\n",
+		},
+		{
+			name:  "SaveFile",
+			input: expectedSave,
+		},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := pattern.Read(strings.NewReader(testDatum.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, newPuzzle(), actual)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	err := pattern.Write(&buf, newPuzzle())
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedEmptySave, buf.String())
+}
+
+func newPuzzle() *ast.Puzzle {
+	x := ast.ForegroundChar
+
+	return &ast.Puzzle{
+		Background: ast.BackgroundChar,
+		Colors:     ast.MonochromeColors(),
+		Clue: ast.Clue{
+			Columns: []ast.Line{
+				{{Color: x, Count: 2}},
+				{{Color: x, Count: 1}, {Color: x, Count: 1}},
+				{{Color: x, Count: 2}},
+			},
+			Rows: []ast.Line{
+				{{Color: x, Count: 2}},
+				{{Color: x, Count: 1}, {Color: x, Count: 1}},
+				{{Color: x, Count: 2}},
+			},
+		},
+	}
+}
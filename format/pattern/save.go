@@ -0,0 +1,331 @@
+package pattern
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/tools"
+)
+
+const (
+	saveFileKey   = "SAVEFILE"
+	saveFileValue = "Simon Tatham's Portable Puzzle Collection"
+	saveVersion   = "1"
+	gameName      = "Pattern"
+	keyLength     = 8
+
+	// maxProgressCells limits the progress grid, unlike clues its size isn't proportional to the input.
+	maxProgressCells = 1 << 22
+)
+
+// Save is a saved game of Pattern.
+type Save struct {
+	Puzzle *ast.Puzzle
+
+	// Progress contains the cells marked by the player: ast.ForegroundChar, ast.BackgroundChar or Unknown.
+	// Nil means nothing is marked.
+	Progress *ast.Goal
+}
+
+type record struct {
+	key   string
+	value string
+	line  int
+}
+
+// ReadSave reads a save file from io.Reader. Moves undone before saving are dropped.
+func ReadSave(r io.Reader) (*Save, error) {
+	records, err := readRecords(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 || records[0].key != saveFileKey || records[0].value != saveFileValue {
+		return nil, syntaxError(1, fmt.Errorf("%w: not a save file", errors.ErrSyntax))
+	}
+
+	var (
+		save          Save
+		width, height int
+		states        = -1
+	)
+
+	for _, rec := range records[1:] {
+		if err = save.apply(rec, &width, &height, &states); err != nil {
+			return nil, syntaxError(rec.line, err)
+		}
+	}
+
+	if save.Puzzle == nil {
+		return nil, syntaxError(records[len(records)-1].line, fmt.Errorf("%w: game description is missing", errors.ErrSyntax))
+	}
+
+	return &save, nil
+}
+
+// WriteSave writes the save file to io.Writer, the progress is written as moves.
+func WriteSave(w io.Writer, save *Save) error {
+	foreground, err := tools.Foreground(*save.Puzzle)
+	if err != nil {
+		return err
+	}
+
+	moves, err := progressMoves(save, foreground)
+	if err != nil {
+		return err
+	}
+
+	b := bufio.NewWriter(w)
+	params := formatParams(save.Puzzle)
+	states := strconv.Itoa(len(moves) + 1)
+
+	writeRecord(b, saveFileKey, saveFileValue)
+	writeRecord(b, "VERSION", saveVersion)
+	writeRecord(b, "GAME", gameName)
+	writeRecord(b, "PARAMS", params)
+	writeRecord(b, "CPARAMS", params)
+	writeRecord(b, "DESC", formatDesc(save.Puzzle))
+	writeRecord(b, "NSTATES", states)
+	writeRecord(b, "STATEPOS", states)
+
+	for _, move := range moves {
+		writeRecord(b, "MOVE", move)
+	}
+
+	return b.Flush()
+}
+
+// apply applies the record to the save, states counts the moves still to be applied.
+func (s *Save) apply(rec record, width, height, states *int) (err error) {
+	switch rec.key {
+	case "VERSION":
+		if rec.value != saveVersion {
+			return fmt.Errorf(`%w: unsupported save file version "%s"`, errors.ErrSyntax, rec.value)
+		}
+	case "GAME":
+		if rec.value != gameName {
+			return fmt.Errorf(`%w: the game is "%s", not %s`, errors.ErrSyntax, rec.value, gameName)
+		}
+	case "PARAMS":
+		*width, *height, err = parseParams(rec.value)
+	case "DESC":
+		s.Puzzle, err = parseDesc(rec.value, *width, *height)
+	case "STATEPOS":
+		if *states, err = strconv.Atoi(rec.value); err != nil || *states <= 0 {
+			return fmt.Errorf(`%w: wrong state position "%s"`, errors.ErrSyntax, rec.value)
+		}
+
+		*states--
+	case "MOVE", "SOLVE", "RESTART":
+		if *states == 0 {
+			return nil
+		}
+
+		*states--
+
+		if s.Puzzle == nil {
+			return fmt.Errorf("%w: move before game description", errors.ErrSyntax)
+		}
+
+		if rec.key == "RESTART" {
+			s.Progress = nil
+
+			return nil
+		}
+
+		return s.move(rec.value, len(s.Puzzle.Clue.Columns), len(s.Puzzle.Clue.Rows))
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", rec.key, err)
+	}
+
+	return nil
+}
+
+// move applies a move: "F", "E" or "U" with the rectangle "x,y,w,h" or "S" with the solution of "0" and "1".
+func (s *Save) move(move string, width, height int) error {
+	if s.Progress == nil {
+		if height > 0 && width > maxProgressCells/height {
+			return fmt.Errorf("%w: progress of %dx%d cells", errors.ErrLimitExceeded, width, height)
+		}
+
+		progress := make(ast.Goal, height)
+		for y := range progress {
+			progress[y] = make([]ast.Char, width)
+			for x := range progress[y] {
+				progress[y][x] = Unknown
+			}
+		}
+
+		s.Progress = &progress
+	}
+
+	progress := *s.Progress
+
+	if move == "" {
+		return fmt.Errorf("%w: empty move", errors.ErrSyntax)
+	}
+
+	switch move[0] {
+	case 'S':
+		if len(move)-1 != width*height {
+			return fmt.Errorf("%w: solve move should have %d cells, got %d", errors.ErrSyntax, width*height, len(move)-1)
+		}
+
+		for i, c := range move[1:] {
+			ch := ast.BackgroundChar
+			if c == '1' {
+				ch = ast.ForegroundChar
+			}
+
+			progress[i/width][i%width] = ch
+		}
+
+		return nil
+	case 'F', 'E', 'U':
+		rect := strings.Split(move[1:], ",")
+		if len(rect) != 4 {
+			return fmt.Errorf(`%w: wrong move "%s"`, errors.ErrSyntax, move)
+		}
+
+		var n [4]int
+		for i := range rect {
+			v, err := strconv.Atoi(rect[i])
+			if err != nil || v < 0 {
+				return fmt.Errorf(`%w: wrong move "%s"`, errors.ErrSyntax, move)
+			}
+
+			n[i] = v
+		}
+
+		x, y, w, h := n[0], n[1], n[2], n[3]
+		if x > width || w > width-x || y > height || h > height-y {
+			return fmt.Errorf(`%w: move "%s" is out of the grid`, errors.ErrSyntax, move)
+		}
+
+		ch := map[byte]ast.Char{'F': ast.ForegroundChar, 'E': ast.BackgroundChar, 'U': Unknown}[move[0]]
+		for row := y; row < y+h; row++ {
+			for col := x; col < x+w; col++ {
+				progress[row][col] = ch
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf(`%w: unknown move "%s"`, errors.ErrSyntax, move)
+}
+
+// progressMoves returns moves marking the progress cells, a move per run of equal cells in a row.
+func progressMoves(save *Save, foreground ast.Char) ([]string, error) {
+	if save.Progress == nil {
+		return nil, nil
+	}
+
+	width, height := len(save.Puzzle.Clue.Columns), len(save.Puzzle.Clue.Rows)
+	if len(*save.Progress) != height {
+		return nil, fmt.Errorf("progress should have %d rows, got %d", height, len(*save.Progress))
+	}
+
+	var moves []string
+
+	for y, row := range *save.Progress {
+		if len(row) != width {
+			return nil, fmt.Errorf("progress row %d should have %d cells, got %d", y, width, len(row))
+		}
+
+		for x := 0; x < width; {
+			n := 1
+			for x+n < width && row[x+n] == row[x] {
+				n++
+			}
+
+			switch row[x] {
+			case foreground:
+				moves = append(moves, fmt.Sprintf("F%d,%d,%d,1", x, y, n))
+			case save.Puzzle.Background:
+				moves = append(moves, fmt.Sprintf("E%d,%d,%d,1", x, y, n))
+			case Unknown:
+			default:
+				return nil, fmt.Errorf(`%w: unexpected progress char "%s"`, errors.ErrMonochromeOnly, string(row[x]))
+			}
+
+			x += n
+		}
+	}
+
+	return moves, nil
+}
+
+// readRecords reads "KEY:length:value" records.
+func readRecords(r io.Reader) ([]record, error) {
+	b := bufio.NewReader(r)
+	line := 1
+
+	var records []record
+
+	for {
+		key, err := b.ReadString(':')
+		if err == io.EOF && strings.TrimSpace(key) == "" {
+			return records, nil
+		}
+
+		if err != nil {
+			return nil, syntaxError(line, fmt.Errorf("%w: unexpected end of record", errors.ErrSyntax))
+		}
+
+		key = strings.TrimLeft(key, "\r\n")
+		rec := record{key: strings.TrimRight(key[:len(key)-1], " "), line: line}
+
+		length, err := b.ReadString(':')
+		if err != nil {
+			return nil, syntaxError(line, fmt.Errorf("%w: unexpected end of record", errors.ErrSyntax))
+		}
+
+		n, err := strconv.Atoi(length[:len(length)-1])
+		if err != nil || n < 0 {
+			return nil, syntaxError(line, fmt.Errorf(`%w: wrong length of "%s"`, errors.ErrSyntax, rec.key))
+		}
+
+		var value strings.Builder
+		if _, err = io.CopyN(&value, b, int64(n)); err != nil {
+			return nil, syntaxError(line, fmt.Errorf(`%w: value of "%s" is too short`, errors.ErrSyntax, rec.key))
+		}
+
+		rec.value = value.String()
+		records = append(records, rec)
+		line += strings.Count(rec.value, "\n")
+
+		for {
+			c, err := b.ReadByte()
+			if err != nil {
+				return records, nil
+			}
+
+			if c == '\n' {
+				line++
+			} else if c != '\r' {
+				_ = b.UnreadByte()
+
+				break
+			}
+		}
+	}
+}
+
+func writeRecord(w io.Writer, key, value string) {
+	fmt.Fprintf(w, "%-*s:%d:%s\n", keyLength, key, len(value), value)
+}
+
+func syntaxError(line int, err error) error {
+	return &errors.SyntaxError{
+		Position: errors.Position{Line: line},
+		Err:      err,
+	}
+}
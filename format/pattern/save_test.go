@@ -0,0 +1,221 @@
+package pattern_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/format/pattern"
+	"github.com/stretchr/testify/assert"
+)
+
+const expectedSave = `SAVEFILE:41:Simon Tatham's Portable Puzzle Collection
+VERSION :1:1
+GAME    :7:Pattern
+PARAMS  :3:3x3
+CPARAMS :3:3x3
+DESC    :15:2/1.1/2/2/1.1/2
+NSTATES :1:5
+STATEPOS:1:5
+MOVE    :8:F0,0,2,1
+MOVE    :8:E2,0,1,1
+MOVE    :8:F2,1,1,1
+MOVE    :8:F2,2,1,1
+`
+
+const expectedEmptySave = `SAVEFILE:41:Simon Tatham's Portable Puzzle Collection
+VERSION :1:1
+GAME    :7:Pattern
+PARAMS  :3:3x3
+CPARAMS :3:3x3
+DESC    :15:2/1.1/2/2/1.1/2
+NSTATES :1:1
+STATEPOS:1:1
+`
+
+func TestReadSave(t *testing.T) {
+	t.Parallel()
+
+	x, o, u := ast.ForegroundChar, ast.BackgroundChar, pattern.Unknown
+
+	testData := [...]struct {
+		name     string
+		input    string
+		expected *ast.Goal
+	}{
+		{
+			name:  "Ok",
+			input: expectedSave,
+			expected: &ast.Goal{
+				{x, x, o},
+				{u, u, x},
+				{u, u, x},
+			},
+		},
+		{
+			name:     "OkWithoutMoves",
+			input:    expectedEmptySave,
+			expected: nil,
+		},
+		{
+			name: "OkWithUndoneMovesAndCRLF",
+			input: "SAVEFILE:41:Simon Tatham's Portable Puzzle Collection\r\n" +
+				"VERSION :1:1\r\nGAME    :7:Pattern\r\nPARAMS  :3:3x3\r\nDESC    :15:2/1.1/2/2/1.1/2\r\n" +
+				"NSTATES :1:5\r\nSTATEPOS:1:3\r\n" +
+				"MOVE    :8:F0,0,2,1\r\nMOVE    :8:U1,0,1,1\r\nMOVE    :8:E2,0,1,1\r\nMOVE    :8:F1,0,1,1\r\n",
+			expected: &ast.Goal{
+				{x, u, u},
+				{u, u, u},
+				{u, u, u},
+			},
+		},
+		{
+			name: "OkWithSolveAndRestart",
+			input: "SAVEFILE:41:Simon Tatham's Portable Puzzle Collection\n" +
+				"VERSION :1:1\nGAME    :7:Pattern\nPARAMS  :3:3x3\nDESC    :15:2/1.1/2/2/1.1/2\n" +
+				"MOVE    :8:E0,0,3,3\nRESTART :15:2/1.1/2/2/1.1/2\nSOLVE   :10:S110101011\n",
+			expected: &ast.Goal{
+				{x, x, o},
+				{x, o, x},
+				{o, x, x},
+			},
+		},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := pattern.ReadSave(strings.NewReader(testDatum.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, &pattern.Save{Puzzle: newPuzzle(), Progress: testDatum.expected}, actual)
+		})
+	}
+
+	errorData := [...]struct {
+		name  string
+		input string
+		line  int
+	}{
+		{
+			name:  "ErrorCauseNotSaveFile",
+			input: "VERSION :1:1\n",
+			line:  1,
+		},
+		{
+			name:  "ErrorCauseWrongGame",
+			input: "SAVEFILE:41:Simon Tatham's Portable Puzzle Collection\nVERSION :1:1\nGAME    :4:Net\n",
+			line:  3,
+		},
+		{
+			name:  "ErrorCauseWrongLength",
+			input: "SAVEFILE:41:Simon Tatham's Portable Puzzle Collection\nVERSION :1:1\nGAME    :10:Pattern\n",
+			line:  3,
+		},
+		{
+			name:  "ErrorCauseNoDescription",
+			input: "SAVEFILE:41:Simon Tatham's Portable Puzzle Collection\nVERSION :1:1\nPARAMS  :3:3x3\n",
+			line:  3,
+		},
+		{
+			name: "ErrorCauseMoveOutOfGrid",
+			input: "SAVEFILE:41:Simon Tatham's Portable Puzzle Collection\n" +
+				"PARAMS  :3:3x3\nDESC    :15:2/1.1/2/2/1.1/2\nMOVE    :8:F2,0,2,1\n",
+			line: 4,
+		},
+		{
+			name: "ErrorCauseMoveOverflow",
+			input: "SAVEFILE:41:Simon Tatham's Portable Puzzle Collection\n" +
+				"PARAMS  :3:3x3\nDESC    :15:2/1.1/2/2/1.1/2\nMOVE    :25:F2,0,9223372036854775807,1\n",
+			line: 4,
+		},
+		{
+			name: "ErrorCauseParamsAfterDescription",
+			input: "SAVEFILE:41:Simon Tatham's Portable Puzzle Collection\n" +
+				"PARAMS  :3:3x3\nDESC    :15:2/1.1/2/2/1.1/2\nPARAMS  :19:1000000000x1000000\nMOVE    :12:F999,0,1,1\n",
+			line: 5,
+		},
+		{
+			name:  "ErrorCauseHugeLength",
+			input: "SAVEFILE:41:Simon Tatham's Portable Puzzle Collection\nVERSION :9223372036854775807:1\n",
+			line:  2,
+		},
+		{
+			name: "ErrorCauseHugeProgress",
+			input: "SAVEFILE:41:Simon Tatham's Portable Puzzle Collection\n" +
+				"PARAMS  :9:4096x4096\nDESC    :8191:" + strings.Repeat("/", 8191) + "\nMOVE    :8:F0,0,1,1\n",
+			line: 4,
+		},
+		{
+			name: "ErrorCauseUnknownMove",
+			input: "SAVEFILE:41:Simon Tatham's Portable Puzzle Collection\n" +
+				"PARAMS  :3:3x3\nDESC    :15:2/1.1/2/2/1.1/2\nMOVE    :1:M\n",
+			line: 4,
+		},
+	}
+
+	for _, testDatum := range errorData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := pattern.ReadSave(strings.NewReader(testDatum.input))
+
+			var syntaxError *errors.SyntaxError
+
+			assert.Nil(t, actual)
+			assert.ErrorIs(t, err, errors.ErrSyntax)
+			assert.ErrorAs(t, err, &syntaxError)
+			assert.Equal(t, testDatum.line, syntaxError.Position.Line)
+		})
+	}
+}
+
+func TestWriteSave(t *testing.T) {
+	t.Parallel()
+
+	x, o, u := ast.ForegroundChar, ast.BackgroundChar, pattern.Unknown
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		err := pattern.WriteSave(&buf, &pattern.Save{
+			Puzzle: newPuzzle(),
+			Progress: &ast.Goal{
+				{x, x, o},
+				{u, u, x},
+				{u, u, x},
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedSave, buf.String())
+	})
+
+	t.Run("ErrorCauseMultiColored", func(t *testing.T) {
+		t.Parallel()
+
+		puzzle := newPuzzle()
+		puzzle.Colors[ast.Char('y')] = ast.Color{R: 255}
+
+		err := pattern.WriteSave(&bytes.Buffer{}, &pattern.Save{Puzzle: puzzle})
+
+		assert.ErrorIs(t, err, errors.ErrMonochromeOnly)
+	})
+
+	t.Run("ErrorCauseProgressSize", func(t *testing.T) {
+		t.Parallel()
+
+		err := pattern.WriteSave(&bytes.Buffer{}, &pattern.Save{Puzzle: newPuzzle(), Progress: &ast.Goal{{x}}})
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,342 @@
+package webpbn
+
+import (
+	"encoding/xml"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+)
+
+// Read set of puzzles from io.Reader. The document root is either <puzzleset> or a single <puzzle>.
+// Puzzles aren't validated.
+func Read(r io.Reader) (*ast.PuzzleSet, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Entity = xml.HTMLEntity
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, syntaxError(err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		var set puzzleSet
+
+		switch start.Name.Local {
+		case "puzzleset":
+			err = decoder.DecodeElement(&set, &start)
+		case "puzzle":
+			set.Puzzles = make([]puzzle, 1)
+			err = decoder.DecodeElement(&set.Puzzles[0], &start)
+		default:
+			err = fmt.Errorf(`%w: unexpected root element <%s>`, errors.ErrSyntax, start.Name.Local)
+		}
+
+		if err != nil {
+			return nil, syntaxError(err)
+		}
+
+		return set.toAST()
+	}
+}
+
+func (s *puzzleSet) toAST() (*ast.PuzzleSet, error) {
+	puzzleSet := make(ast.PuzzleSet, 0, len(s.Puzzles))
+
+	for i := range s.Puzzles {
+		p := &s.Puzzles[i]
+		p.inherit(s)
+
+		puzzle, err := p.toAST()
+		if err != nil {
+			return nil, fmt.Errorf("puzzle #%d: %w", i, err)
+		}
+
+		puzzleSet = append(puzzleSet, *puzzle)
+	}
+
+	return &puzzleSet, nil
+}
+
+// inherit fills empty puzzle metadata from the puzzle set.
+func (p *puzzle) inherit(s *puzzleSet) {
+	if p.Source == "" {
+		p.Source = s.Source
+	}
+
+	if p.Author == "" {
+		p.Author = s.Author
+	}
+
+	if p.AuthorID == "" {
+		p.AuthorID = s.AuthorID
+	}
+
+	if p.Copyright == "" {
+		p.Copyright = s.Copyright
+	}
+}
+
+func (p *puzzle) toAST() (*ast.Puzzle, error) {
+	if p.Type != "" && p.Type != "grid" {
+		return nil, fmt.Errorf(`%w: unsupported puzzle type "%s"`, errors.ErrSyntax, p.Type)
+	}
+
+	puzzle := &ast.Puzzle{
+		ID:          strings.TrimSpace(p.ID),
+		Source:      strings.TrimSpace(p.Source),
+		Copyright:   strings.TrimSpace(p.Copyright),
+		Title:       strings.TrimSpace(p.Title),
+		Description: strings.TrimSpace(p.Description),
+		Colors:      ast.Colors{},
+	}
+
+	if p.Author != "" || p.AuthorID != "" {
+		puzzle.Author = &ast.Author{
+			Name: strings.TrimSpace(p.Author),
+			ID:   strings.TrimSpace(p.AuthorID),
+		}
+	}
+
+	palette, err := p.palette()
+	if err != nil {
+		return nil, err
+	}
+
+	use := func(name string) (ast.Char, error) {
+		c, ok := palette[name]
+		if !ok {
+			return 0, fmt.Errorf(`%w: undefined color "%s"`, errors.ErrSyntax, name)
+		}
+
+		puzzle.Colors[c.char] = c.color
+
+		return c.char, nil
+	}
+
+	if puzzle.Background, err = use(or(p.BackgroundColor, backgroundColor)); err != nil {
+		return nil, err
+	}
+
+	for _, c := range p.Colors {
+		if _, err := use(c.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, c := range p.Clues {
+		lines, err := c.toAST(or(p.DefaultColor, defaultColor), use)
+		if err != nil {
+			return nil, err
+		}
+
+		switch c.Type {
+		case "columns":
+			puzzle.Clue.Columns = lines
+		case "rows":
+			puzzle.Clue.Rows = lines
+		default:
+			return nil, fmt.Errorf(`%w: unexpected clues type "%s"`, errors.ErrSyntax, c.Type)
+		}
+	}
+
+	for _, s := range p.Solutions {
+		if s.Type != "" && s.Type != "goal" {
+			continue
+		}
+
+		goal, err := s.toAST(palette, use)
+		if err != nil {
+			return nil, err
+		}
+
+		puzzle.Goal = goal
+
+		break
+	}
+
+	return puzzle, nil
+}
+
+type paletteColor struct {
+	char  ast.Char
+	color ast.Color
+}
+
+// predefined colors are available in every puzzle even if they aren't declared.
+var predefined = [...]struct {
+	name string
+	paletteColor
+}{
+	{name: backgroundColor, paletteColor: paletteColor{char: ast.Char('.'), color: ast.Color{R: 255, G: 255, B: 255}}},
+	{name: defaultColor, paletteColor: paletteColor{char: ast.Char('X')}},
+}
+
+// palette returns puzzle colors by name. Colors declared without char get the first free one.
+func (p *puzzle) palette() (map[string]paletteColor, error) {
+	palette := map[string]paletteColor{}
+	taken := map[ast.Char]bool{}
+	preferred := map[string]ast.Char{}
+
+	var unassigned []string
+
+	for _, c := range p.Colors {
+		var col ast.Color
+		if err := col.UnmarshalText([]byte("#" + strings.TrimPrefix(strings.TrimSpace(c.Value), "#"))); err != nil {
+			return nil, err
+		}
+
+		palette[c.Name] = paletteColor{color: col}
+
+		if c.Char == "" {
+			unassigned = append(unassigned, c.Name)
+			continue
+		}
+
+		var ch ast.Char
+		if err := ch.UnmarshalText([]byte(c.Char)); err != nil {
+			return nil, err
+		}
+
+		if taken[ch] {
+			return nil, fmt.Errorf(`%w: char "%s" of color "%s" has already been used`, errors.ErrSyntax, c.Char, c.Name)
+		}
+
+		taken[ch] = true
+		palette[c.Name] = paletteColor{char: ch, color: col}
+	}
+
+	for _, d := range predefined {
+		preferred[d.name] = d.char
+
+		if _, ok := palette[d.name]; !ok {
+			palette[d.name] = d.paletteColor
+			unassigned = append(unassigned, d.name)
+		}
+	}
+
+	for _, name := range unassigned {
+		ch, ok := preferred[name]
+		if !ok {
+			r, _ := utf8.DecodeRuneInString(name)
+			ch = ast.Char(r)
+		}
+
+		c := palette[name]
+		if c.char = freeChar(ch, taken); c.char == 0 {
+			return nil, fmt.Errorf(`%w: no free char for color "%s"`, errors.ErrSyntax, name)
+		}
+
+		taken[c.char] = true
+		palette[name] = c
+	}
+
+	return palette, nil
+}
+
+// freeChar returns the preferred char if it isn't taken or the first unused one.
+func freeChar(preferred ast.Char, taken map[ast.Char]bool) ast.Char {
+	if preferred != 0 && !taken[preferred] {
+		var ch ast.Char
+		if err := ch.UnmarshalText([]byte(string(preferred))); err == nil {
+			return preferred
+		}
+	}
+
+	for _, r := range unusedChars {
+		if !taken[ast.Char(r)] {
+			return ast.Char(r)
+		}
+	}
+
+	return 0
+}
+
+func (c *clues) toAST(defaultColor string, use func(string) (ast.Char, error)) ([]ast.Line, error) {
+	lines := make([]ast.Line, 0, len(c.Lines))
+
+	for _, l := range c.Lines {
+		line := ast.Line{}
+
+		for _, cnt := range l.Counts {
+			ch, err := use(or(cnt.Color, defaultColor))
+			if err != nil {
+				return nil, err
+			}
+
+			line = append(line, ast.Item{Color: ch, Count: cnt.Value})
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+func (s *solution) toAST(palette map[string]paletteColor, use func(string) (ast.Char, error)) (*ast.Goal, error) {
+	names := map[ast.Char]string{}
+	for name, c := range palette {
+		names[c.char] = name
+	}
+
+	var goal ast.Goal
+
+	for _, row := range strings.Split(string(s.Image), "\n") {
+		row = strings.TrimSpace(row)
+		if row == "" {
+			continue
+		}
+
+		if strings.HasPrefix(row, "|") && strings.HasSuffix(row, "|") && len(row) > 1 {
+			row = row[1 : len(row)-1]
+		}
+
+		cells := make([]ast.Char, 0, len(row))
+		for _, r := range row {
+			name, ok := names[ast.Char(r)]
+			if !ok {
+				return nil, fmt.Errorf(`%w: unexpected char "%s" in solution image`, errors.ErrSyntax, string(r))
+			}
+
+			ch, err := use(name)
+			if err != nil {
+				return nil, err
+			}
+
+			cells = append(cells, ch)
+		}
+
+		goal = append(goal, cells)
+	}
+
+	return &goal, nil
+}
+
+func syntaxError(err error) error {
+	var xmlError *xml.SyntaxError
+	if !goerrors.As(err, &xmlError) {
+		return err
+	}
+
+	return &errors.SyntaxError{
+		Position: errors.Position{Line: xmlError.Line},
+		Err:      err,
+	}
+}
+
+func or(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
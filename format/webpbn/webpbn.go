@@ -0,0 +1,78 @@
+// Package webpbn reads and writes puzzles in Jan Wolter's webpbn XML format, see https://webpbn.com/pbn_fmt.html.
+package webpbn
+
+import "encoding/xml"
+
+const (
+	defaultColor    = "black"
+	backgroundColor = "white"
+	unusedChars     = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	doctype         = `<!DOCTYPE pbn SYSTEM "https://webpbn.com/pbn-0.3.dtd">`
+)
+
+type puzzleSet struct {
+	XMLName   xml.Name `xml:"puzzleset"`
+	Source    string   `xml:"source,omitempty"`
+	Title     string   `xml:"title,omitempty"`
+	Author    string   `xml:"author,omitempty"`
+	AuthorID  string   `xml:"authorid,omitempty"`
+	Copyright string   `xml:"copyright,omitempty"`
+	Puzzles   []puzzle `xml:"puzzle"`
+}
+
+type puzzle struct {
+	XMLName         xml.Name   `xml:"puzzle"`
+	Type            string     `xml:"type,attr,omitempty"`
+	DefaultColor    string     `xml:"defaultcolor,attr,omitempty"`
+	BackgroundColor string     `xml:"backgroundcolor,attr,omitempty"`
+	Source          string     `xml:"source,omitempty"`
+	ID              string     `xml:"id,omitempty"`
+	Title           string     `xml:"title,omitempty"`
+	Author          string     `xml:"author,omitempty"`
+	AuthorID        string     `xml:"authorid,omitempty"`
+	Copyright       string     `xml:"copyright,omitempty"`
+	Description     string     `xml:"description,omitempty"`
+	Colors          []color    `xml:"color"`
+	Clues           []clues    `xml:"clues"`
+	Solutions       []solution `xml:"solution"`
+}
+
+type color struct {
+	XMLName xml.Name `xml:"color"`
+	Name    string   `xml:"name,attr"`
+	Char    string   `xml:"char,attr,omitempty"`
+	Value   string   `xml:",chardata"`
+}
+
+type clues struct {
+	Type  string `xml:"type,attr"`
+	Lines []line `xml:"line"`
+}
+
+type line struct {
+	Counts []count `xml:"count"`
+}
+
+type count struct {
+	Color string `xml:"color,attr,omitempty"`
+	Value int    `xml:",chardata"`
+}
+
+type solution struct {
+	Type  string `xml:"type,attr,omitempty"`
+	Image image  `xml:"image"`
+}
+
+// image of the solution, rows are written on separate lines.
+type image string
+
+// MarshalXML writes the image keeping line breaks unescaped.
+func (i image) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	for _, token := range []xml.Token{start, xml.CharData(i), start.End()} {
+		if err := e.EncodeToken(token); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,352 @@
+package webpbn_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alexeyco/hanjie"
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/format/webpbn"
+	"github.com/stretchr/testify/assert"
+)
+
+const puzzleSetXML = `<?xml version="1.0"?>
+<!DOCTYPE pbn SYSTEM "https://webpbn.com/pbn-0.3.dtd">
+<puzzleset>
+  <source>https://foo.bar</source>
+  <author>John Doe</author>
+  <authorid>johnDoe</authorid>
+  <puzzle type="grid" defaultcolor="black" backgroundcolor="white">
+    <id>id</id>
+    <title>Puzzle</title>
+    <copyright>&copy; John Doe</copyright>
+    <description>Very beautiful puzzle</description>
+    <color name="white" char=".">fff</color>
+    <color name="black" char="x">000000</color>
+    <clues type="columns">
+      <line><count>2</count></line>
+      <line><count>1</count><count>1</count></line>
+      <line><count>2</count></line>
+    </clues>
+    <clues type="rows">
+      <line><count>2</count></line>
+      <line><count>1</count><count>1</count></line>
+      <line><count>2</count></line>
+    </clues>
+    <solution type="goal">
+      <image>
+      |xx.|
+      |x.x|
+      |.xx|
+      </image>
+    </solution>
+  </puzzle>
+</puzzleset>
+`
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		expected := &ast.PuzzleSet{newPuzzle()}
+
+		actual, err := webpbn.Read(strings.NewReader(puzzleSetXML))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("OkSinglePuzzleWithPredefinedColors", func(t *testing.T) {
+		t.Parallel()
+
+		input := `<puzzle>
+  <title>Puzzle</title>
+  <clues type="columns"><line><count>1</count></line><line></line></clues>
+  <clues type="rows"><line><count>1</count></line></clues>
+</puzzle>`
+
+		expected := &ast.PuzzleSet{
+			{
+				Title:      "Puzzle",
+				Background: ast.Char('.'),
+				Colors: ast.Colors{
+					ast.Char('.'): ast.Color{R: 255, G: 255, B: 255},
+					ast.Char('X'): ast.Color{},
+				},
+				Clue: ast.Clue{
+					Columns: []ast.Line{
+						{{Color: ast.Char('X'), Count: 1}},
+						{},
+					},
+					Rows: []ast.Line{
+						{{Color: ast.Char('X'), Count: 1}},
+					},
+				},
+			},
+		}
+
+		actual, err := webpbn.Read(strings.NewReader(input))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("OkMultiColored", func(t *testing.T) {
+		t.Parallel()
+
+		input := `<puzzle defaultcolor="red">
+  <title>Puzzle</title>
+  <color name="white">fff</color>
+  <color name="red">f00</color>
+  <color name="green" char="g">0f0</color>
+  <clues type="columns"><line><count>1</count></line><line><count color="green">1</count></line></clues>
+  <clues type="rows"><line><count>1</count><count color="green">1</count></line></clues>
+  <solution><image>|rg|</image></solution>
+</puzzle>`
+
+		expected := &ast.PuzzleSet{
+			{
+				Title:      "Puzzle",
+				Background: ast.Char('.'),
+				Colors: ast.Colors{
+					ast.Char('.'): ast.Color{R: 255, G: 255, B: 255},
+					ast.Char('r'): ast.Color{R: 255},
+					ast.Char('g'): ast.Color{G: 255},
+				},
+				Clue: ast.Clue{
+					Columns: []ast.Line{
+						{{Color: ast.Char('r'), Count: 1}},
+						{{Color: ast.Char('g'), Count: 1}},
+					},
+					Rows: []ast.Line{
+						{{Color: ast.Char('r'), Count: 1}, {Color: ast.Char('g'), Count: 1}},
+					},
+				},
+				Goal: &ast.Goal{
+					{ast.Char('r'), ast.Char('g')},
+				},
+			},
+		}
+
+		actual, err := webpbn.Read(strings.NewReader(input))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	testData := [...]struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "ErrorCauseUndefinedColor",
+			input: `<puzzle><clues type="rows"><line><count color="red">1</count></line></clues></puzzle>`,
+		},
+		{
+			name:  "ErrorCauseUnexpectedImageChar",
+			input: `<puzzle><solution><image>|X?|</image></solution></puzzle>`,
+		},
+		{
+			name:  "ErrorCauseUnsupportedType",
+			input: `<puzzle type="triddler"></puzzle>`,
+		},
+		{
+			name:  "ErrorCauseUnexpectedRoot",
+			input: `<html></html>`,
+		},
+		{
+			name:  "ErrorCauseWrongColor",
+			input: `<puzzle><color name="red">zzz</color></puzzle>`,
+		},
+		{
+			name:  "ErrorCauseDuplicateChar",
+			input: `<puzzle><color name="red" char="r">f00</color><color name="rose" char="r">f0f</color></puzzle>`,
+		},
+		{
+			name:  "ErrorCauseMalformedXML",
+			input: "<puzzleset>\n<puzzle>\n</puzzleset>",
+		},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := webpbn.Read(strings.NewReader(testDatum.input))
+
+			assert.Nil(t, actual)
+			assert.ErrorIs(t, err, errors.ErrSyntax)
+		})
+	}
+}
+
+func newPuzzle() ast.Puzzle {
+	return ast.Puzzle{
+		ID:     "id",
+		Source: "https://foo.bar",
+		Author: &ast.Author{
+			Name: "John Doe",
+			ID:   "johnDoe",
+		},
+		Copyright:   "© John Doe",
+		Title:       "Puzzle",
+		Description: "Very beautiful puzzle",
+		Background:  ast.Char('.'),
+		Colors: ast.Colors{
+			ast.Char('.'): ast.Color{R: 255, G: 255, B: 255},
+			ast.Char('x'): ast.Color{},
+		},
+		Clue: ast.Clue{
+			Columns: []ast.Line{
+				{
+					{Color: ast.Char('x'), Count: 2},
+				},
+				{
+					{Color: ast.Char('x'), Count: 1}, {Color: ast.Char('x'), Count: 1},
+				},
+				{
+					{Color: ast.Char('x'), Count: 2},
+				},
+			},
+			Rows: []ast.Line{
+				{
+					{Color: ast.Char('x'), Count: 2},
+				},
+				{
+					{Color: ast.Char('x'), Count: 1}, {Color: ast.Char('x'), Count: 1},
+				},
+				{
+					{Color: ast.Char('x'), Count: 2},
+				},
+			},
+		},
+		Goal: &ast.Goal{
+			{ast.Char('x'), ast.Char('x'), ast.Char('.')},
+			{ast.Char('x'), ast.Char('.'), ast.Char('x')},
+			{ast.Char('.'), ast.Char('x'), ast.Char('x')},
+		},
+	}
+}
+
+const expectedXML = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE pbn SYSTEM "https://webpbn.com/pbn-0.3.dtd">
+<puzzleset>
+  <puzzle type="grid" defaultcolor="g" backgroundcolor="white">
+    <title>Puzzle</title>
+    <color name="white" char=".">ffffff</color>
+    <color name="g" char="g">00ff00</color>
+    <color name="r" char="r">ff0000</color>
+    <clues type="columns">
+      <line>
+        <count color="r">1</count>
+      </line>
+      <line>
+        <count>1</count>
+      </line>
+    </clues>
+    <clues type="rows">
+      <line>
+        <count color="r">1</count>
+        <count>1</count>
+      </line>
+    </clues>
+    <solution type="goal">
+      <image>
+      |rg|
+      </image>
+    </solution>
+  </puzzle>
+</puzzleset>
+`
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	puzzleSet := &ast.PuzzleSet{
+		{
+			Title:      "Puzzle",
+			Background: ast.Char('.'),
+			Colors: ast.Colors{
+				ast.Char('.'): ast.Color{R: 255, G: 255, B: 255},
+				ast.Char('r'): ast.Color{R: 255},
+				ast.Char('g'): ast.Color{G: 255},
+			},
+			Clue: ast.Clue{
+				Columns: []ast.Line{
+					{{Color: ast.Char('r'), Count: 1}},
+					{{Color: ast.Char('g'), Count: 1}},
+				},
+				Rows: []ast.Line{
+					{{Color: ast.Char('r'), Count: 1}, {Color: ast.Char('g'), Count: 1}},
+				},
+			},
+			Goal: &ast.Goal{
+				{ast.Char('r'), ast.Char('g')},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	err := webpbn.Write(&buf, puzzleSet)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedXML, buf.String())
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	t.Run("XML", func(t *testing.T) {
+		t.Parallel()
+
+		expected, err := webpbn.Read(strings.NewReader(puzzleSetXML))
+		assert.NoError(t, err)
+
+		var buf bytes.Buffer
+
+		assert.NoError(t, webpbn.Write(&buf, expected))
+
+		actual, err := webpbn.Read(&buf)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("YAML", func(t *testing.T) {
+		t.Parallel()
+
+		expected := &ast.PuzzleSet{newPuzzle()}
+
+		var yamlBuf bytes.Buffer
+
+		assert.NoError(t, hanjie.Write(&yamlBuf, expected))
+
+		puzzleSet, err := hanjie.Read(&yamlBuf)
+		assert.NoError(t, err)
+
+		var xmlBuf bytes.Buffer
+
+		assert.NoError(t, webpbn.Write(&xmlBuf, puzzleSet))
+
+		actual, err := webpbn.Read(&xmlBuf)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+
+		yamlBuf.Reset()
+
+		assert.NoError(t, hanjie.Write(&yamlBuf, actual))
+
+		roundTripped, err := hanjie.Read(&yamlBuf)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, roundTripped)
+	})
+}
@@ -0,0 +1,171 @@
+package webpbn
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/alexeyco/hanjie/ast"
+)
+
+const imageIndent = "      "
+
+// Write set of puzzles to io.Writer.
+// Colors are named after their chars, pure white and black get the predefined names. The puzzle background
+// becomes backgroundcolor and the most used clue color becomes defaultcolor. Puzzles aren't validated.
+func Write(w io.Writer, puzzleSet *ast.PuzzleSet) error {
+	set := puzzleSet2XML(*puzzleSet)
+
+	if _, err := io.WriteString(w, xml.Header+doctype+"\n"); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(set); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+func puzzleSet2XML(puzzles ast.PuzzleSet) *puzzleSet {
+	set := &puzzleSet{
+		Puzzles: make([]puzzle, 0, len(puzzles)),
+	}
+
+	for i := range puzzles {
+		set.Puzzles = append(set.Puzzles, puzzle2XML(puzzles[i]))
+	}
+
+	return set
+}
+
+func puzzle2XML(p ast.Puzzle) puzzle {
+	names := colorNames(p.Colors)
+	defaultChar := mostUsedColor(p.Clue)
+
+	x := puzzle{
+		Type:            "grid",
+		DefaultColor:    names[defaultChar],
+		BackgroundColor: names[p.Background],
+		Source:          p.Source,
+		ID:              p.ID,
+		Title:           p.Title,
+		Copyright:       p.Copyright,
+		Description:     p.Description,
+	}
+
+	if p.Author != nil {
+		x.Author = p.Author.Name
+		x.AuthorID = p.Author.ID
+	}
+
+	for _, ch := range p.Colors.Chars() {
+		c := p.Colors[ch]
+		x.Colors = append(x.Colors, color{
+			Name:  names[ch],
+			Char:  string(ch),
+			Value: fmt.Sprintf("%02x%02x%02x", c.R, c.G, c.B),
+		})
+	}
+
+	lines2XML := func(typ string, lines []ast.Line) clues {
+		c := clues{Type: typ, Lines: make([]line, 0, len(lines))}
+		for _, l := range lines {
+			xl := line{}
+			for _, item := range l {
+				cnt := count{Value: item.Count}
+				if item.Color != defaultChar {
+					cnt.Color = names[item.Color]
+				}
+
+				xl.Counts = append(xl.Counts, cnt)
+			}
+
+			c.Lines = append(c.Lines, xl)
+		}
+
+		return c
+	}
+
+	x.Clues = []clues{
+		lines2XML("columns", p.Clue.Columns),
+		lines2XML("rows", p.Clue.Rows),
+	}
+
+	if p.Goal != nil {
+		var b strings.Builder
+
+		for _, row := range *p.Goal {
+			b.WriteString("\n" + imageIndent + "|")
+			for _, ch := range row {
+				b.WriteRune(rune(ch))
+			}
+
+			b.WriteString("|")
+		}
+
+		b.WriteString("\n" + imageIndent)
+
+		x.Solutions = []solution{{Type: "goal", Image: image(b.String())}}
+	}
+
+	return x
+}
+
+// colorNames returns unique color names: pure white and black get predefined names, other colors are named
+// after their chars when possible.
+func colorNames(colors ast.Colors) map[ast.Char]string {
+	names := map[ast.Char]string{}
+	taken := map[string]bool{}
+
+	for _, ch := range colors.Chars() {
+		var name string
+
+		switch colors[ch] {
+		case ast.Color{R: 255, G: 255, B: 255}:
+			name = backgroundColor
+		case ast.Color{}:
+			name = defaultColor
+		}
+
+		if (name == "" || taken[name]) && (unicode.IsLetter(rune(ch)) || unicode.IsDigit(rune(ch))) {
+			name = string(ch)
+		}
+
+		for i := len(names); name == "" || taken[name]; i++ {
+			name = fmt.Sprintf("color%d", i)
+		}
+
+		taken[name] = true
+		names[ch] = name
+	}
+
+	return names
+}
+
+func mostUsedColor(clue ast.Clue) ast.Char {
+	used := map[ast.Char]int{}
+	for _, lines := range [][]ast.Line{clue.Columns, clue.Rows} {
+		for _, l := range lines {
+			for _, item := range l {
+				used[item.Color]++
+			}
+		}
+	}
+
+	var mostUsed ast.Char
+	for ch, cnt := range used {
+		if cnt > used[mostUsed] || cnt == used[mostUsed] && ch < mostUsed {
+			mostUsed = ch
+		}
+	}
+
+	return mostUsed
+}
@@ -0,0 +1,222 @@
+package hanjie_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/alexeyco/hanjie"
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/format/webpbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadAny(t *testing.T) {
+	t.Parallel()
+
+	var webpbnBuf bytes.Buffer
+
+	assert.NoError(t, webpbn.Write(&webpbnBuf, expectedPuzzleSet))
+
+	testData := [...]struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "YAML",
+			input: expectedString,
+		},
+		{
+			name:  "JSON",
+			input: expectedJSONString,
+		},
+		{
+			name:  "WebPBN",
+			input: webpbnBuf.String(),
+		},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := hanjie.ReadAny(strings.NewReader(testDatum.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, expectedPuzzleSet, actual)
+		})
+	}
+
+	t.Run("Non", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		assert.NoError(t, hanjie.Write(&buf, expectedPuzzleSet, hanjie.WithFormat(hanjie.FormatNon)))
+
+		actual, err := hanjie.ReadAny(&buf)
+
+		assert.NoError(t, err)
+		assert.Len(t, *actual, 1)
+		assert.Equal(t, (*expectedPuzzleSet)[0].Title, (*actual)[0].Title)
+		assert.Equal(t, (*expectedPuzzleSet)[0].Clue, (*actual)[0].Clue)
+		assert.Equal(t, (*expectedPuzzleSet)[0].Goal, (*actual)[0].Goal)
+	})
+
+	t.Run("Pattern", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		assert.NoError(t, hanjie.Write(&buf, expectedPuzzleSet, hanjie.WithFormat(hanjie.FormatPattern)))
+
+		for _, input := range []string{buf.String(), "3x3:2/1.1/2/2/1.1/2\n"} {
+			actual, err := hanjie.ReadAny(strings.NewReader(input), hanjie.SkipValidation)
+
+			assert.NoError(t, err)
+			assert.Len(t, *actual, 1)
+			assert.Equal(t, (*expectedPuzzleSet)[0].Clue, (*actual)[0].Clue)
+		}
+	})
+
+	for _, name := range []string{hanjie.FormatOlsak, hanjie.FormatNin, hanjie.FormatGno} {
+		name := name
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			assert.NoError(t, hanjie.Write(&buf, expectedPuzzleSet, hanjie.WithFormat(name)))
+
+			actual, err := hanjie.ReadAny(&buf, hanjie.SkipValidation)
+
+			assert.NoError(t, err)
+			assert.Len(t, *actual, 1)
+			assert.Equal(t, (*expectedPuzzleSet)[0].Clue, (*actual)[0].Clue)
+		})
+	}
+
+	t.Run("ErrorCauseNoTitle", func(t *testing.T) {
+		t.Parallel()
+
+		for _, name := range []string{hanjie.FormatPattern, hanjie.FormatOlsak, hanjie.FormatNin} {
+			var buf bytes.Buffer
+
+			assert.NoError(t, hanjie.Write(&buf, expectedPuzzleSet, hanjie.WithFormat(name)))
+
+			actual, err := hanjie.ReadAny(&buf)
+
+			assert.Nil(t, actual, name)
+			assert.ErrorIs(t, err, errors.ErrEmptyTitle, name)
+		}
+	})
+
+	t.Run("Registered", func(t *testing.T) {
+		t.Parallel()
+
+		hanjie.RegisterFormat("test-read-any", func(head []byte) bool {
+			return bytes.HasPrefix(head, []byte("TEST"))
+		}, func(r io.Reader) (*ast.PuzzleSet, error) {
+			_, err := io.ReadAll(r)
+
+			return expectedPuzzleSet, err
+		}, nil)
+
+		actual, err := hanjie.ReadAny(strings.NewReader("TEST"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPuzzleSet, actual)
+	})
+}
+
+func TestRead_WithFormat(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ErrorCauseUnknownFormat", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := hanjie.Read(strings.NewReader(expectedString), hanjie.WithFormat("unknown"))
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrUnknownFormat)
+	})
+
+	t.Run("ErrorPositionedInFile", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := hanjie.Read(strings.NewReader("<puzzle>\n<foo>\n</puzzle>"),
+			hanjie.WithFormat(hanjie.FormatWebPBN), hanjie.WithFilename("foo.xml"))
+
+		var syntaxError *errors.SyntaxError
+
+		assert.Nil(t, actual)
+		assert.ErrorAs(t, err, &syntaxError)
+		assert.Equal(t, errors.Position{File: "foo.xml", Line: 3}, syntaxError.Position)
+	})
+}
+
+func TestWrite_WithFormat(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		var expected, actual bytes.Buffer
+
+		assert.NoError(t, webpbn.Write(&expected, expectedPuzzleSet))
+		assert.NoError(t, hanjie.Write(&actual, expectedPuzzleSet, hanjie.WithFormat(hanjie.FormatWebPBN)))
+		assert.Equal(t, expected.String(), actual.String())
+	})
+
+	t.Run("ErrorCauseUnknownFormat", func(t *testing.T) {
+		t.Parallel()
+
+		err := hanjie.Write(io.Discard, expectedPuzzleSet, hanjie.WithFormat("unknown"))
+
+		assert.ErrorIs(t, err, errors.ErrUnknownFormat)
+	})
+
+	t.Run("ErrorCauseSinglePuzzle", func(t *testing.T) {
+		t.Parallel()
+
+		puzzleSet := append(ast.PuzzleSet{}, *expectedPuzzleSet...)
+		puzzleSet = append(puzzleSet, puzzleSet[0])
+
+		err := hanjie.Write(io.Discard, &puzzleSet, hanjie.WithFormat(hanjie.FormatNon))
+
+		assert.ErrorIs(t, err, errors.ErrSinglePuzzle)
+	})
+
+	t.Run("ErrorCauseReadOnlyFormat", func(t *testing.T) {
+		t.Parallel()
+
+		hanjie.RegisterFormat("test-read-only", nil, func(r io.Reader) (*ast.PuzzleSet, error) {
+			return expectedPuzzleSet, nil
+		}, nil)
+
+		err := hanjie.Write(io.Discard, expectedPuzzleSet, hanjie.WithFormat("test-read-only"))
+
+		assert.ErrorIs(t, err, errors.ErrReadOnlyFormat)
+	})
+}
+
+func TestRegisterFormat(t *testing.T) {
+	t.Parallel()
+
+	t.Run("PanicNilDecoder", func(t *testing.T) {
+		t.Parallel()
+
+		assert.PanicsWithValue(t, `hanjie: RegisterFormat decoder of format "test-nil-decoder" is nil`, func() {
+			hanjie.RegisterFormat("test-nil-decoder", nil, nil, nil)
+		})
+
+		_, err := hanjie.Read(strings.NewReader(""), hanjie.WithFormat("test-nil-decoder"))
+
+		assert.ErrorIs(t, err, errors.ErrUnknownFormat)
+	})
+}
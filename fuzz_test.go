@@ -0,0 +1,61 @@
+//go:build go1.18
+// +build go1.18
+
+package hanjie_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/alexeyco/hanjie"
+	"github.com/alexeyco/hanjie/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+func FuzzRead(f *testing.F) {
+	for _, seed := range []string{
+		expectedString,
+		legacyString + untitledString,
+		expectedJSONString,
+		`<puzzleset><puzzle><clues type="columns"><line><count>1</count></line></clues></puzzle></puzzleset>`,
+		"width 2\nheight 1\nrows\n1\ncolumns\n1\n0\ngoal \"10\"\n",
+		"2x1:1/0/1,",
+		"SAVEFILE:41:Simon Tatham's Portable Puzzle Collection\nGAME    :7:Pattern\nPARAMS  :3:2x1\nDESC    :5:1/0/1\nMOVE    :8:F0,0,1,1\n",
+		"#d\n: rows\n1\n: columns\n1\n",
+		"1 1\n1\n1\n",
+		"[Dimensions]\n1\n1\n[Row clues]\n1\n[Column clues]\n1\n[Solution]\n2\n",
+		"version: 2\npuzzles:\n  - title: Puzzle\n    background: .\n    colors: {.: '#ffffff', x: '#000000'}\n" +
+			"    clue: {columns: [\"1\", \"1\", \"1\"], rows: [\"9223372036854775807 1\"]}\n",
+		"0: [:!00 \xef",
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		limits := hanjie.WithLimits(32, 32, 16, 8, 1<<16)
+
+		puzzleSet, err := hanjie.ReadAny(bytes.NewReader(data), limits)
+		if err == nil {
+			assert.NoError(t, hanjie.Write(io.Discard, puzzleSet))
+		}
+
+		_, _ = hanjie.Read(bytes.NewReader(data), limits, hanjie.Strict, hanjie.KeepValid)
+		_, _ = hanjie.ReadJSON(bytes.NewReader(data), limits, hanjie.Strict)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		solving := validator.New(validator.WithContext(ctx), validator.UniqueSolution, validator.Solvable)
+		_, _ = hanjie.Read(bytes.NewReader(data), limits, hanjie.WithValidator(solving))
+
+		decoder := hanjie.NewDecoder(bytes.NewReader(data), limits)
+		for i := 0; i < 16; i++ {
+			if _, err := decoder.Decode(); err == io.EOF {
+				break
+			}
+		}
+	})
+}
@@ -2,11 +2,17 @@
 package hanjie
 
 import (
+	"bytes"
+	goerrors "errors"
+	"fmt"
 	"io"
+	"strconv"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/tools"
 )
 
 // Validator interface.
@@ -14,27 +20,311 @@ type Validator interface {
 	Validate(ast.PuzzleSet) error
 }
 
-// Read set of puzzles from io.Reader.
+// Read set of puzzles from io.Reader, the format is YAML unless WithFormat option is set.
+// Puzzles are validated after decoding unless SkipValidation is set. By default an invalid puzzle
+// fails the whole set, with KeepValid the valid puzzles are returned along with errors.ValidationError.
+// Syntax and validation errors are reported with positions in the source document.
+// Puzzles exceeding limits set by WithLimits fail the whole set.
 func Read(r io.Reader, options ...Option) (*ast.PuzzleSet, error) {
-	var puzzleSet ast.PuzzleSet
-	if err := yaml.NewDecoder(r).Decode(&puzzleSet); err != nil {
+	collection, err := ReadCollection(r, options...)
+	if collection == nil {
 		return nil, err
 	}
 
+	return &collection.Puzzles, err
+}
+
+// ReadCollection reads collection of puzzles from io.Reader the same way Read does.
+// Documents without metadata, e.g. bare lists of puzzles, give collections of puzzles only.
+func ReadCollection(r io.Reader, options ...Option) (*ast.Collection, error) {
 	o := newOptions()
 	for _, opt := range options {
 		opt(&o)
 	}
 
-	return &puzzleSet, nil
+	f, err := lookupFormat(o.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	limited := limitReader(r, o)
+
+	collection, puzzleNode, err := f.read(limited, o)
+	if err != nil {
+		return nil, limited.cause(err)
+	}
+
+	if err = checkLimits(o, collection.Puzzles); err != nil {
+		if puzzleNode != nil {
+			locate(o, puzzleNode, err)
+		}
+
+		return nil, err
+	}
+
+	valid, err := validate(o, collection.Puzzles)
+	if puzzleNode != nil {
+		locate(o, puzzleNode, err)
+	}
+
+	if err != nil && !o.KeepValid {
+		return nil, err
+	}
+
+	collection.Puzzles = valid
+
+	return collection, err
 }
 
 // Write set of puzzles to io.Writer.
+// Puzzles are validated before encoding the same way Read does. Nil set is written as an empty one.
 func Write(w io.Writer, puzzleSet *ast.PuzzleSet, options ...Option) error {
+	collection := &ast.Collection{}
+	if puzzleSet != nil {
+		collection.Puzzles = *puzzleSet
+	}
+
+	return WriteCollection(w, collection, options...)
+}
+
+// WriteCollection writes collection of puzzles to io.Writer the same way Write does.
+// Formats without the collection metadata, including YAML of version 1, write puzzles only.
+// Nil collection is written as an empty one.
+func WriteCollection(w io.Writer, collection *ast.Collection, options ...Option) error {
+	if collection == nil {
+		collection = &ast.Collection{}
+	}
+
 	o := newOptions()
 	for _, opt := range options {
 		opt(&o)
 	}
 
-	return yaml.NewEncoder(w).Encode(puzzleSet)
+	f, err := lookupFormat(o.Format)
+	if err != nil {
+		return err
+	}
+
+	if f.write == nil {
+		return fmt.Errorf(`%w "%s"`, errors.ErrReadOnlyFormat, f.name)
+	}
+
+	valid, err := validate(o, writable(o, collection.Puzzles))
+	if err != nil && !o.KeepValid {
+		return err
+	}
+
+	written := *collection
+	written.Puzzles = valid
+
+	if writeErr := f.write(w, &written, o); writeErr != nil {
+		return writeErr
+	}
+
+	return err
+}
+
+// Canonicalize returns set of puzzles written in the canonical form, see Canonical.
+// The result suits for hashing, or for checking if a file is formatted by comparing it with the file contents.
+func Canonicalize(puzzleSet *ast.PuzzleSet, options ...Option) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := Write(&buf, puzzleSet, append(options, Canonical)...); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func readYAML(r io.Reader, o Options) (*ast.Collection, func(int) *yaml.Node, error) {
+	_, puzzles, collection, err := decodeYAML(r, o)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return collection, func(index int) *yaml.Node {
+		return child(puzzles, strconv.Itoa(index))
+	}, nil
+}
+
+// decodeYAML returns the source document, the node of puzzles upgraded to CurrentVersion and the collection.
+func decodeYAML(r io.Reader, o Options) (*yaml.Node, *yaml.Node, *ast.Collection, error) {
+	var document yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&document); err != nil {
+		return nil, nil, nil, syntaxError(o, err)
+	}
+
+	puzzles, err := upgrade(o, &document)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if o.Strict {
+		if err := checkYAMLFields(o, &document, documentType(&document)); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	var collection ast.Collection
+
+	if root := resolve(&document); root.Kind == yaml.MappingNode {
+		err = root.Decode(&collection)
+	} else {
+		err = puzzles.Decode(&collection.Puzzles)
+	}
+
+	if err != nil {
+		return nil, nil, nil, syntaxError(o, err)
+	}
+
+	return &document, puzzles, &collection, nil
+}
+
+func writeYAML(w io.Writer, collection *ast.Collection, o Options) error {
+	document, err := encodeDocument(collection, o)
+	if err != nil {
+		return err
+	}
+
+	return encodeYAML(w, document)
+}
+
+// encodeDocument returns the document of the collection of the version set by options.
+func encodeDocument(collection *ast.Collection, o Options) (*yaml.Node, error) {
+	var puzzles yaml.Node
+	if err := puzzles.Encode(collection.Puzzles); err != nil {
+		return nil, err
+	}
+
+	return encodePuzzles(&puzzles, collection, o)
+}
+
+// encodePuzzles returns the document of the version set by options holding the encoded puzzles.
+// Metadata of the collection is written to documents of version 2 and later.
+func encodePuzzles(puzzles *yaml.Node, collection *ast.Collection, o Options) (*yaml.Node, error) {
+	document, err := downgrade(puzzles, o.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	if document.Kind != yaml.MappingNode {
+		return document, nil
+	}
+
+	metadata := *collection
+	metadata.Puzzles = nil
+
+	var encoded yaml.Node
+	if err = encoded.Encode(metadata); err != nil {
+		return nil, err
+	}
+
+	var content []*yaml.Node
+	for i := 0; i+1 < len(encoded.Content); i += 2 {
+		if encoded.Content[i].Value != puzzlesKey {
+			content = append(content, encoded.Content[i], encoded.Content[i+1])
+		}
+	}
+
+	// Metadata goes between the version and the puzzles.
+	document.Content = append(document.Content[:2], append(content, document.Content[2:]...)...)
+
+	return document, nil
+}
+
+// encodeYAML writes the document, documents of version 2 and later are indented by two spaces.
+func encodeYAML(w io.Writer, document *yaml.Node) error {
+	encoder := yaml.NewEncoder(w)
+	if resolve(document).Kind == yaml.MappingNode {
+		encoder.SetIndent(2)
+	}
+
+	return encoder.Encode(document)
+}
+
+// validate returns puzzles to be kept and the validation error if any.
+func validate(o Options, puzzleSet ast.PuzzleSet) (ast.PuzzleSet, error) {
+	valid, _, err := validateIndexed(o, puzzleSet)
+
+	return valid, err
+}
+
+// validateIndexed validates puzzles the same way validate does and returns indexes of the valid puzzles in the set.
+func validateIndexed(o Options, puzzleSet ast.PuzzleSet) (ast.PuzzleSet, []int, error) {
+	if o.SkipValidation || o.Validator == nil || !o.KeepValid {
+		indexes := make([]int, len(puzzleSet))
+		for i := range indexes {
+			indexes[i] = i
+		}
+
+		var err error
+		if !o.SkipValidation && o.Validator != nil {
+			err = o.Validator.Validate(puzzleSet)
+		}
+
+		return puzzleSet, indexes, err
+	}
+
+	valid := ast.PuzzleSet{}
+	indexes := []int{}
+
+	var validationError errors.ValidationError
+	for i := range puzzleSet {
+		err := o.Validator.Validate(puzzleSet[i : i+1 : i+1])
+		if err == nil {
+			valid = append(valid, puzzleSet[i])
+			indexes = append(indexes, i)
+
+			continue
+		}
+
+		reindex(err, i)
+
+		var ve errors.ValidationError
+		if !goerrors.As(err, &ve) {
+			ve = errors.ValidationError{err}
+		}
+
+		validationError = append(validationError, ve...)
+	}
+
+	if len(validationError) == 0 {
+		return valid, indexes, nil
+	}
+
+	return valid, indexes, validationError
+}
+
+// writable returns a copy of the puzzles to be validated and written, normalized if Canonical is set.
+// Rules fixing puzzles, e.g. validator.FillGoal, change the copy, so writing never changes puzzles of the caller.
+func writable(o Options, puzzleSet ast.PuzzleSet) ast.PuzzleSet {
+	if o.Canonical {
+		return normalize(puzzleSet)
+	}
+
+	return append(ast.PuzzleSet{}, puzzleSet...)
+}
+
+// normalize returns normalized copies of the puzzles.
+func normalize(puzzleSet ast.PuzzleSet) ast.PuzzleSet {
+	normalized := make(ast.PuzzleSet, 0, len(puzzleSet))
+	for _, puzzle := range puzzleSet {
+		normalized = append(normalized, tools.Normalize(puzzle))
+	}
+
+	return normalized
+}
+
+// reindex sets the puzzle index to its errors.
+func reindex(err error, index int) {
+	var validationError errors.ValidationError
+	if !goerrors.As(err, &validationError) {
+		return
+	}
+
+	for _, e := range validationError {
+		var puzzleError *errors.PuzzleError
+		if goerrors.As(e, &puzzleError) {
+			puzzleError.Index = index
+		}
+	}
 }
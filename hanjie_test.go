@@ -7,6 +7,9 @@ import (
 
 	"github.com/alexeyco/hanjie"
 	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/tools"
+	"github.com/alexeyco/hanjie/validator"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -58,7 +61,30 @@ var expectedPuzzleSet = &ast.PuzzleSet{
 	},
 }
 
-var expectedString = `- id: id
+var expectedString = `version: 2
+puzzles:
+  - id: id
+    source: https://foo.bar
+    author:
+      name: John Doe
+      id: johnDoe
+    copyright: '&copy; John Doe'
+    title: Puzzle
+    description: Very beautiful puzzle
+    background: .
+    colors:
+      .: '#ffffff'
+      x: '#000000'
+    clue:
+      columns: ["2", "1 1", "2"]
+      rows: ["2", "1 1", "2"]
+    goal:
+      - xx.
+      - x.x
+      - .xx
+`
+
+var legacyString = `- id: id
   source: https://foo.bar
   author:
     name: John Doe
@@ -76,22 +102,339 @@ var expectedString = `- id: id
   goal: [[x, x, .], [x, ., x], [., x, x]]
 `
 
+// uniqueGoal is the goal of the clue having the only solution.
+var uniqueGoal = ast.Goal{
+	{ast.Char('x'), ast.Char('x'), ast.Char('.')},
+	{ast.Char('x'), ast.Char('.'), ast.Char('.')},
+	{ast.Char('.'), ast.Char('.'), ast.Char('.')},
+}
+
+var untitledString = `- background: .
+  colors:
+    .: '#ffffff'
+    x: '#000000'
+  clue:
+    columns: [[{color: x, count: 1}]]
+    rows: [[{color: x, count: 1}]]
+`
+
 func TestRead(t *testing.T) {
 	t.Parallel()
 
-	actual, err := hanjie.Read(strings.NewReader(expectedString))
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := hanjie.Read(strings.NewReader(expectedString))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPuzzleSet, actual)
+	})
+
+	t.Run("OkVersion1", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := hanjie.Read(strings.NewReader(legacyString))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPuzzleSet, actual)
+	})
+
+	t.Run("OkVersion1Compact", func(t *testing.T) {
+		t.Parallel()
+
+		input := strings.Replace(expectedString, "version: 2\npuzzles:\n", "", 1)
+
+		actual, err := hanjie.Read(strings.NewReader(input))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPuzzleSet, actual)
+	})
+
+	t.Run("OkFillGoal", func(t *testing.T) {
+		t.Parallel()
+
+		puzzleSet := ast.PuzzleSet{(*expectedPuzzleSet)[0]}
+		puzzleSet[0].Clue = tools.GoalToClue(uniqueGoal, ast.Char('.'))
+		puzzleSet[0].Goal = nil
+
+		var buf bytes.Buffer
+
+		assert.NoError(t, hanjie.Write(&buf, &puzzleSet))
+
+		actual, err := hanjie.Read(&buf, hanjie.WithValidator(validator.New(validator.FillGoal)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, &uniqueGoal, (*actual)[0].Goal)
+	})
+
+	t.Run("OkSkipValidation", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := hanjie.Read(strings.NewReader(untitledString), hanjie.SkipValidation)
+
+		assert.NoError(t, err)
+		assert.Len(t, *actual, 1)
+	})
+
+	t.Run("ErrorCauseInvalidPuzzle", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := hanjie.Read(strings.NewReader(legacyString + untitledString))
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrEmptyTitle)
+		assert.IsType(t, errors.ValidationError{}, err)
+	})
+
+	t.Run("ErrorSyntaxPosition", func(t *testing.T) {
+		t.Parallel()
+
+		input := strings.Replace(expectedString, "x: '#000000'", "xy: '#000000'", 1)
+
+		actual, err := hanjie.Read(strings.NewReader(input), hanjie.WithFilename("foo.yml"))
+
+		var syntaxError *errors.SyntaxError
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrSyntax)
+		assert.ErrorAs(t, err, &syntaxError)
+		assert.Equal(t, errors.Position{File: "foo.yml", Line: 14, Column: 7}, syntaxError.Position)
+	})
+
+	t.Run("ErrorYAMLSyntaxPosition", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := hanjie.Read(strings.NewReader("- id: [\n"), hanjie.WithFilename("foo.yml"))
+
+		var syntaxError *errors.SyntaxError
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrSyntax)
+		assert.ErrorAs(t, err, &syntaxError)
+		assert.Equal(t, errors.Position{File: "foo.yml", Line: 1}, syntaxError.Position)
+	})
+
+	t.Run("ErrorValidationPosition", func(t *testing.T) {
+		t.Parallel()
+
+		input := strings.Replace(expectedString, `rows: ["2", "1 1", "2"]`, `rows: ["2", "1 1", "3"]`, 1)
+
+		actual, err := hanjie.Read(strings.NewReader(input), hanjie.WithFilename("foo.yml"))
+
+		var puzzleError *errors.PuzzleError
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrGoalDoesNotMatchTheClue)
+		assert.ErrorAs(t, err, &puzzleError)
+		assert.Equal(t, "clue.rows[2][0].count", puzzleError.Field)
+		assert.Equal(t, errors.Position{File: "foo.yml", Line: 17, Column: 27}, puzzleError.Position)
+	})
+
+	t.Run("ErrorUnsolvablePosition", func(t *testing.T) {
+		t.Parallel()
+
+		input := strings.Replace(expectedString, `rows: ["2", "1 1", "2"]`, `rows: ["2", "1 1", "4"]`, 1)
+		input = input[:strings.Index(input, "    goal:")]
+
+		actual, err := hanjie.Read(strings.NewReader(input),
+			hanjie.WithValidator(validator.New(validator.Solvable)), hanjie.WithFilename("foo.yml"))
+
+		var puzzleError *errors.PuzzleError
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrUnsolvable)
+		assert.ErrorAs(t, err, &puzzleError)
+		assert.Equal(t, "clue.rows[2]", puzzleError.Field)
+		assert.Equal(t, errors.Position{File: "foo.yml", Line: 17, Column: 26}, puzzleError.Position)
+	})
+
+	t.Run("ErrorCauseUnsupportedVersion", func(t *testing.T) {
+		t.Parallel()
+
+		input := strings.Replace(expectedString, "version: 2", "version: 3", 1)
+
+		actual, err := hanjie.Read(strings.NewReader(input), hanjie.WithFilename("foo.yml"))
+
+		var syntaxError *errors.SyntaxError
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrUnsupportedVersion)
+		assert.ErrorAs(t, err, &syntaxError)
+		assert.Equal(t, errors.Position{File: "foo.yml", Line: 1, Column: 10}, syntaxError.Position)
+	})
+
+	t.Run("ErrorCauseMalformedVersion", func(t *testing.T) {
+		t.Parallel()
 
-	assert.NoError(t, err)
-	assert.Equal(t, expectedPuzzleSet, actual)
+		input := strings.Replace(expectedString, "version: 2", "version: two", 1)
+
+		actual, err := hanjie.Read(strings.NewReader(input))
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrSyntax)
+	})
+
+	t.Run("ErrorCauseMissingVersion", func(t *testing.T) {
+		t.Parallel()
+
+		input := strings.Replace(expectedString, "version: 2\n", "", 1)
+
+		actual, err := hanjie.Read(strings.NewReader(input))
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrSyntax)
+	})
+
+	t.Run("ErrorKeepValid", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := hanjie.Read(strings.NewReader(legacyString+untitledString), hanjie.KeepValid)
+
+		assert.Equal(t, expectedPuzzleSet, actual)
+		assert.ErrorIs(t, err, errors.ErrEmptyTitle)
+		assert.IsType(t, errors.ValidationError{}, err)
+
+		var puzzleError *errors.PuzzleError
+
+		assert.ErrorAs(t, err, &puzzleError)
+		assert.Equal(t, 1, puzzleError.Index)
+		assert.Equal(t, 17, puzzleError.Position.Line)
+	})
 }
 
 func TestWrite(t *testing.T) {
 	t.Parallel()
 
-	var buf bytes.Buffer
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		err := hanjie.Write(&buf, expectedPuzzleSet)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedString, buf.String())
+	})
+
+	t.Run("OkVersion1", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		err := hanjie.Write(&buf, expectedPuzzleSet, hanjie.WithVersion(1))
+
+		assert.NoError(t, err)
+		assert.Equal(t, legacyString, buf.String())
+	})
+
+	t.Run("OkNilPuzzleSet", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		err := hanjie.Write(&buf, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "version: 2\npuzzles: []\n", buf.String())
+	})
+
+	t.Run("OkFillGoalKeepsPuzzles", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		puzzleSet := ast.PuzzleSet{(*expectedPuzzleSet)[0]}
+		puzzleSet[0].Clue = tools.GoalToClue(uniqueGoal, ast.Char('.'))
+		puzzleSet[0].Goal = nil
+
+		err := hanjie.Write(&buf, &puzzleSet, hanjie.WithValidator(validator.New(validator.FillGoal)))
+
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), "    goal:\n      - xx.\n      - x..\n      - '...'\n")
+		assert.Nil(t, puzzleSet[0].Goal)
+	})
+
+	t.Run("ErrorCauseUnsupportedVersion", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		err := hanjie.Write(&buf, expectedPuzzleSet, hanjie.WithVersion(3))
+
+		assert.ErrorIs(t, err, errors.ErrUnsupportedVersion)
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("ErrorCauseInvalidPuzzle", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		puzzleSet := ast.PuzzleSet{(*expectedPuzzleSet)[0], (*expectedPuzzleSet)[0]}
+		puzzleSet[1].Title = ""
+
+		err := hanjie.Write(&buf, &puzzleSet)
+
+		assert.ErrorIs(t, err, errors.ErrEmptyTitle)
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("ErrorKeepValid", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		puzzleSet := ast.PuzzleSet{(*expectedPuzzleSet)[0], (*expectedPuzzleSet)[0]}
+		puzzleSet[1].Title = ""
+
+		err := hanjie.Write(&buf, &puzzleSet, hanjie.KeepValid)
+
+		assert.ErrorIs(t, err, errors.ErrEmptyTitle)
+		assert.Equal(t, expectedString, buf.String())
+	})
+}
+
+func TestCanonicalize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		puzzleSet := ast.PuzzleSet{(*expectedPuzzleSet)[0]}
+		puzzleSet[0].Colors = ast.Colors{}
+
+		for _, ch := range []ast.Char{ast.Char('x'), ast.Char('.')} {
+			puzzleSet[0].Colors[ch] = (*expectedPuzzleSet)[0].Colors[ch]
+		}
+
+		puzzleSet[0].Clue.Rows = append([]ast.Line{
+			{{Color: ast.Char('x'), Count: 0}, {Color: ast.Char('x'), Count: 2}},
+		}, puzzleSet[0].Clue.Rows[1:]...)
+
+		actual, err := hanjie.Canonicalize(&puzzleSet)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedString, string(actual))
+	})
+
+	t.Run("OkFormat", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := hanjie.Canonicalize(expectedPuzzleSet, hanjie.WithFormat(hanjie.FormatJSON))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedJSONDocument, string(actual))
+	})
+
+	t.Run("ErrorCauseInvalidPuzzle", func(t *testing.T) {
+		t.Parallel()
+
+		puzzleSet := ast.PuzzleSet{(*expectedPuzzleSet)[0]}
+		puzzleSet[0].Title = ""
 
-	err := hanjie.Write(&buf, expectedPuzzleSet)
+		actual, err := hanjie.Canonicalize(&puzzleSet)
 
-	assert.NoError(t, err)
-	assert.Equal(t, expectedString, buf.String())
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrEmptyTitle)
+	})
 }
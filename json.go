@@ -0,0 +1,200 @@
+package hanjie
+
+import (
+	"bytes"
+	"encoding/json"
+	goerrors "errors"
+	"io"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+)
+
+// ReadJSON reads set of puzzles in JSON from io.Reader, it's a shortcut for Read with WithFormat(FormatJSON).
+// The document is either a list of puzzles or an object with the version, the collection metadata and the list
+// of puzzles.
+func ReadJSON(r io.Reader, options ...Option) (*ast.PuzzleSet, error) {
+	return Read(r, append(options, WithFormat(FormatJSON))...)
+}
+
+// WriteJSON writes set of puzzles in JSON to io.Writer, it's a shortcut for Write with WithFormat(FormatJSON).
+// The document is an object with the version and the list of puzzles the same way YAML is,
+// version 1 set by WithVersion is a list of puzzles.
+func WriteJSON(w io.Writer, puzzleSet *ast.PuzzleSet, options ...Option) error {
+	return Write(w, puzzleSet, append(options, WithFormat(FormatJSON))...)
+}
+
+func readJSON(r io.Reader, o Options) (*ast.Collection, func(int) *yaml.Node, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	envelope := isJSONObject(data)
+
+	if o.Strict {
+		t := reflect.TypeOf(ast.PuzzleSet{})
+		if envelope {
+			t = reflect.TypeOf(strictDocument{})
+		}
+
+		if err := checkJSONFields(o, data, t); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if !envelope {
+		var puzzleSet ast.PuzzleSet
+		if err := json.Unmarshal(data, &puzzleSet); err != nil {
+			return nil, nil, syntaxError(o, jsonSyntaxError(data, err))
+		}
+
+		return &ast.Collection{Puzzles: puzzleSet}, nil, nil
+	}
+
+	var document jsonDocument
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, nil, syntaxError(o, jsonSyntaxError(data, err))
+	}
+
+	if err := checkVersion(document.Version); err != nil {
+		return nil, nil, err
+	}
+
+	return &document.Collection, nil, nil
+}
+
+// jsonDocument is the document of version 2 and later.
+type jsonDocument struct {
+	Version int `json:"version"`
+	ast.Collection
+}
+
+// writeJSON writes the collection as an object with the version, the metadata and the list of puzzles.
+// Version 1 set by WithVersion writes the list of puzzles only.
+func writeJSON(w io.Writer, collection *ast.Collection, o Options) error {
+	if err := checkVersion(o.Version); err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+
+	if o.Version == 1 {
+		puzzles, err := downgradeJSON(collection.Puzzles)
+		if err != nil {
+			return err
+		}
+
+		return encoder.Encode(puzzles)
+	}
+
+	return encoder.Encode(jsonDocument{Version: o.Version, Collection: *collection})
+}
+
+// downgradeJSON returns puzzles encoded in JSON of version 1, which knows no goal row strings,
+// so goal rows are written as arrays of chars. Clue lines are arrays of items in every version.
+func downgradeJSON(puzzleSet ast.PuzzleSet) ([]json.RawMessage, error) {
+	puzzles := make([]json.RawMessage, 0, len(puzzleSet))
+
+	for _, puzzle := range puzzleSet {
+		data, err := puzzle.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+
+		if puzzle.Goal != nil {
+			goal, err := json.Marshal([][]ast.Char(*puzzle.Goal))
+			if err != nil {
+				return nil, err
+			}
+
+			if data, err = replaceJSONValue(data, "goal", goal); err != nil {
+				return nil, err
+			}
+		}
+
+		puzzles = append(puzzles, data)
+	}
+
+	return puzzles, nil
+}
+
+// replaceJSONValue replaces the value of the key of the JSON object keeping the order of keys.
+func replaceJSONValue(data []byte, key string, value []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		start := decoder.InputOffset()
+
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, err
+		}
+
+		if token == key {
+			end := decoder.InputOffset()
+
+			replaced := append(append(append([]byte{}, data[:start]...), ':'), value...)
+
+			return append(replaced, data[end:]...), nil
+		}
+	}
+
+	return data, nil
+}
+
+func isJSONObject(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+// jsonSyntaxError returns decoding error positioned in the source file.
+func jsonSyntaxError(data []byte, err error) error {
+	var offset int64
+
+	var (
+		jsonSyntaxError *json.SyntaxError
+		typeError       *json.UnmarshalTypeError
+	)
+
+	switch {
+	case goerrors.As(err, &jsonSyntaxError):
+		offset = jsonSyntaxError.Offset
+	case goerrors.As(err, &typeError):
+		offset = typeError.Offset
+	case goerrors.Is(err, errors.ErrSyntax):
+	default:
+		return err
+	}
+
+	return &errors.SyntaxError{
+		Position: jsonPosition(data, offset),
+		Err:      err,
+	}
+}
+
+// jsonPosition returns position of the byte offset.
+func jsonPosition(data []byte, offset int64) errors.Position {
+	var pos errors.Position
+	if offset > 0 && offset <= int64(len(data)) {
+		before := data[:offset]
+		pos.Line = bytes.Count(before, []byte("\n")) + 1
+		pos.Column = len(before) - bytes.LastIndexByte(before, '\n')
+	}
+
+	return pos
+}
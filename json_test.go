@@ -0,0 +1,395 @@
+package hanjie_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alexeyco/hanjie"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var expectedJSONString = `[
+  {
+    "id": "id",
+    "source": "https://foo.bar",
+    "author": {
+      "name": "John Doe",
+      "id": "johnDoe"
+    },
+    "copyright": "&copy; John Doe",
+    "title": "Puzzle",
+    "description": "Very beautiful puzzle",
+    "background": ".",
+    "colors": {
+      ".": "#ffffff",
+      "x": "#000000"
+    },
+    "clue": {
+      "columns": [
+        [
+          {
+            "color": "x",
+            "count": 2
+          }
+        ],
+        [
+          {
+            "color": "x",
+            "count": 1
+          },
+          {
+            "color": "x",
+            "count": 1
+          }
+        ],
+        [
+          {
+            "color": "x",
+            "count": 2
+          }
+        ]
+      ],
+      "rows": [
+        [
+          {
+            "color": "x",
+            "count": 2
+          }
+        ],
+        [
+          {
+            "color": "x",
+            "count": 1
+          },
+          {
+            "color": "x",
+            "count": 1
+          }
+        ],
+        [
+          {
+            "color": "x",
+            "count": 2
+          }
+        ]
+      ]
+    },
+    "goal": [
+      "xx.",
+      "x.x",
+      ".xx"
+    ]
+  }
+]
+`
+
+// legacyJSONString is the list of puzzles of version 1, goal rows are arrays of chars.
+var legacyJSONString = `[
+  {
+    "id": "id",
+    "source": "https://foo.bar",
+    "author": {
+      "name": "John Doe",
+      "id": "johnDoe"
+    },
+    "copyright": "&copy; John Doe",
+    "title": "Puzzle",
+    "description": "Very beautiful puzzle",
+    "background": ".",
+    "colors": {
+      ".": "#ffffff",
+      "x": "#000000"
+    },
+    "clue": {
+      "columns": [
+        [
+          {
+            "color": "x",
+            "count": 2
+          }
+        ],
+        [
+          {
+            "color": "x",
+            "count": 1
+          },
+          {
+            "color": "x",
+            "count": 1
+          }
+        ],
+        [
+          {
+            "color": "x",
+            "count": 2
+          }
+        ]
+      ],
+      "rows": [
+        [
+          {
+            "color": "x",
+            "count": 2
+          }
+        ],
+        [
+          {
+            "color": "x",
+            "count": 1
+          },
+          {
+            "color": "x",
+            "count": 1
+          }
+        ],
+        [
+          {
+            "color": "x",
+            "count": 2
+          }
+        ]
+      ]
+    },
+    "goal": [
+      [
+        "x",
+        "x",
+        "."
+      ],
+      [
+        "x",
+        ".",
+        "x"
+      ],
+      [
+        ".",
+        "x",
+        "x"
+      ]
+    ]
+  }
+]
+`
+
+var expectedJSONDocument = `{
+  "version": 2,
+  "puzzles": [
+    {
+      "id": "id",
+      "source": "https://foo.bar",
+      "author": {
+        "name": "John Doe",
+        "id": "johnDoe"
+      },
+      "copyright": "&copy; John Doe",
+      "title": "Puzzle",
+      "description": "Very beautiful puzzle",
+      "background": ".",
+      "colors": {
+        ".": "#ffffff",
+        "x": "#000000"
+      },
+      "clue": {
+        "columns": [
+          [
+            {
+              "color": "x",
+              "count": 2
+            }
+          ],
+          [
+            {
+              "color": "x",
+              "count": 1
+            },
+            {
+              "color": "x",
+              "count": 1
+            }
+          ],
+          [
+            {
+              "color": "x",
+              "count": 2
+            }
+          ]
+        ],
+        "rows": [
+          [
+            {
+              "color": "x",
+              "count": 2
+            }
+          ],
+          [
+            {
+              "color": "x",
+              "count": 1
+            },
+            {
+              "color": "x",
+              "count": 1
+            }
+          ],
+          [
+            {
+              "color": "x",
+              "count": 2
+            }
+          ]
+        ]
+      },
+      "goal": [
+        "xx.",
+        "x.x",
+        ".xx"
+      ]
+    }
+  ]
+}
+`
+
+func TestReadJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := hanjie.ReadJSON(strings.NewReader(expectedJSONString))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPuzzleSet, actual)
+	})
+
+	t.Run("OkGoalAsArrays", func(t *testing.T) {
+		t.Parallel()
+
+		input := strings.Replace(expectedJSONString, `"xx.",
+      "x.x",
+      ".xx"`, `["x", "x", "."], ["x", ".", "x"], [".", "x", "x"]`, 1)
+
+		actual, err := hanjie.ReadJSON(strings.NewReader(input))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPuzzleSet, actual)
+	})
+
+	t.Run("OkCompactLines", func(t *testing.T) {
+		t.Parallel()
+
+		input := `[{"id": "id", "source": "https://foo.bar", "author": {"name": "John Doe", "id": "johnDoe"},
+"copyright": "&copy; John Doe", "title": "Puzzle", "description": "Very beautiful puzzle", "background": ".",
+"colors": {".": "#ffffff", "x": "#000000"}, "clue": {"columns": ["2", "1x 1", "2x"], "rows": ["2", "1 1", "2"]},
+"goal": ["xx.", "x.x", ".xx"]}]`
+
+		actual, err := hanjie.ReadJSON(strings.NewReader(input))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPuzzleSet, actual)
+	})
+
+	t.Run("OkVersionedDocument", func(t *testing.T) {
+		t.Parallel()
+
+		input := `{"version": 2, "puzzles": ` + expectedJSONString + `}`
+
+		actual, err := hanjie.ReadJSON(strings.NewReader(input))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPuzzleSet, actual)
+	})
+
+	t.Run("ErrorCauseUnsupportedVersion", func(t *testing.T) {
+		t.Parallel()
+
+		input := `{"version": 3, "puzzles": ` + expectedJSONString + `}`
+
+		actual, err := hanjie.ReadJSON(strings.NewReader(input))
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrUnsupportedVersion)
+	})
+
+	t.Run("ErrorCauseInvalidPuzzle", func(t *testing.T) {
+		t.Parallel()
+
+		input := strings.Replace(expectedJSONString, `"title": "Puzzle",`, `"title": "",`, 1)
+
+		actual, err := hanjie.ReadJSON(strings.NewReader(input))
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrEmptyTitle)
+	})
+
+	t.Run("ErrorCauseWrongChar", func(t *testing.T) {
+		t.Parallel()
+
+		input := strings.Replace(expectedJSONString, `"background": ".",`, `"background": "..",`, 1)
+
+		actual, err := hanjie.ReadJSON(strings.NewReader(input), hanjie.WithFilename("foo.json"))
+
+		var syntaxError *errors.SyntaxError
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrSyntax)
+		assert.ErrorAs(t, err, &syntaxError)
+		assert.Equal(t, "foo.json", syntaxError.Position.File)
+	})
+
+	t.Run("ErrorCauseMalformedJSON", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := hanjie.ReadJSON(strings.NewReader("[\n  {\"id\": }\n]"), hanjie.WithFilename("foo.json"))
+
+		var syntaxError *errors.SyntaxError
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrSyntax)
+		assert.ErrorAs(t, err, &syntaxError)
+		assert.Equal(t, errors.Position{File: "foo.json", Line: 2, Column: 11}, syntaxError.Position)
+	})
+}
+
+func TestWriteJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		err := hanjie.WriteJSON(&buf, expectedPuzzleSet)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedJSONDocument, buf.String())
+	})
+
+	t.Run("OkVersion1", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		err := hanjie.WriteJSON(&buf, expectedPuzzleSet, hanjie.WithVersion(1))
+
+		assert.NoError(t, err)
+		assert.Equal(t, legacyJSONString, buf.String())
+
+		actual, err := hanjie.ReadJSON(&buf)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPuzzleSet, actual)
+	})
+
+	t.Run("ErrorCauseUnsupportedVersion", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		err := hanjie.WriteJSON(&buf, expectedPuzzleSet, hanjie.WithVersion(3))
+
+		assert.ErrorIs(t, err, errors.ErrUnsupportedVersion)
+		assert.Empty(t, buf.String())
+	})
+}
@@ -0,0 +1,136 @@
+package hanjie
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+)
+
+// Limits of the input, zero means no limit. Use them reading untrusted input, see WithLimits.
+type Limits struct {
+	MaxWidth   int
+	MaxHeight  int
+	MaxColors  int
+	MaxPuzzles int
+	MaxBytes   int64
+}
+
+// checkLimits reports puzzles exceeding the limits, errors of the puzzles are returned in errors.ValidationError.
+func checkLimits(o Options, puzzleSet ast.PuzzleSet) error {
+	limits := o.Limits
+	if limits.MaxPuzzles > 0 && len(puzzleSet) > limits.MaxPuzzles {
+		return fmt.Errorf("%w: %d puzzles, at most %d are allowed", errors.ErrLimitExceeded, len(puzzleSet), limits.MaxPuzzles)
+	}
+
+	var validationError errors.ValidationError
+
+	for i, puzzle := range puzzleSet {
+		for _, puzzleError := range limits.check(puzzle) {
+			puzzleError.Index = i
+			puzzleError.ID = puzzle.ID
+
+			validationError.Append(puzzleError)
+		}
+	}
+
+	if len(validationError) == 0 {
+		return nil
+	}
+
+	return validationError
+}
+
+func (l Limits) check(puzzle ast.Puzzle) []*errors.PuzzleError {
+	var puzzleErrors []*errors.PuzzleError
+
+	exceeded := func(field, what string, n, limit int) {
+		puzzleErrors = append(puzzleErrors, &errors.PuzzleError{
+			Field: field,
+			Err:   fmt.Errorf("%w: %d %s, at most %d are allowed", errors.ErrLimitExceeded, n, what, limit),
+		})
+	}
+
+	if l.MaxWidth > 0 && len(puzzle.Clue.Columns) > l.MaxWidth {
+		exceeded("clue.columns", "columns", len(puzzle.Clue.Columns), l.MaxWidth)
+	}
+
+	if l.MaxHeight > 0 && len(puzzle.Clue.Rows) > l.MaxHeight {
+		exceeded("clue.rows", "rows", len(puzzle.Clue.Rows), l.MaxHeight)
+	}
+
+	if l.MaxColors > 0 && len(puzzle.Colors) > l.MaxColors {
+		exceeded("colors", "colors", len(puzzle.Colors), l.MaxColors)
+	}
+
+	if puzzle.Goal == nil {
+		return puzzleErrors
+	}
+
+	if l.MaxHeight > 0 && len(*puzzle.Goal) > l.MaxHeight {
+		exceeded("goal", "rows", len(*puzzle.Goal), l.MaxHeight)
+	}
+
+	for r, row := range *puzzle.Goal {
+		if l.MaxWidth > 0 && len(row) > l.MaxWidth {
+			exceeded(fmt.Sprintf("goal[%d]", r), "columns", len(row), l.MaxWidth)
+
+			break
+		}
+	}
+
+	return puzzleErrors
+}
+
+// limitedReader reads at most max bytes, reading more fails with errors.ErrLimitExceeded.
+// Decoders may hide the cause of reading errors, so the error is kept to be reported instead.
+type limitedReader struct {
+	r   io.Reader
+	n   int64
+	max int64
+	err error
+}
+
+func limitReader(r io.Reader, o Options) *limitedReader {
+	return &limitedReader{r: r, n: o.Limits.MaxBytes, max: o.Limits.MaxBytes}
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.max <= 0 {
+		return l.r.Read(p)
+	}
+
+	if l.err != nil {
+		return 0, l.err
+	}
+
+	if l.n <= 0 {
+		var b [1]byte
+		if _, err := io.ReadFull(l.r, b[:]); err != nil {
+			return 0, err
+		}
+
+		l.err = fmt.Errorf("%w: input is larger than %d bytes", errors.ErrLimitExceeded, l.max)
+
+		return 0, l.err
+	}
+
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+
+	return n, err
+}
+
+// cause returns the limit error if it's the cause of err.
+func (l *limitedReader) cause(err error) error {
+	if err != nil && l.err != nil {
+		return l.err
+	}
+
+	return err
+}
@@ -0,0 +1,119 @@
+package hanjie_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alexeyco/hanjie"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithLimits(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := hanjie.Read(strings.NewReader(expectedString), hanjie.WithLimits(3, 3, 2, 1, int64(len(expectedString))))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPuzzleSet, actual)
+	})
+
+	testData := [...]struct {
+		name     string
+		limits   hanjie.Option
+		field    string
+		position errors.Position
+	}{
+		{
+			name:     "Width",
+			limits:   hanjie.WithLimits(2, 0, 0, 0, 0),
+			field:    "clue.columns",
+			position: errors.Position{File: "foo.yml", Line: 16, Column: 16},
+		},
+		{
+			name:     "Height",
+			limits:   hanjie.WithLimits(0, 2, 0, 0, 0),
+			field:    "clue.rows",
+			position: errors.Position{File: "foo.yml", Line: 17, Column: 13},
+		},
+		{
+			name:     "Colors",
+			limits:   hanjie.WithLimits(0, 0, 1, 0, 0),
+			field:    "colors",
+			position: errors.Position{File: "foo.yml", Line: 13, Column: 7},
+		},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := hanjie.Read(strings.NewReader(expectedString), testDatum.limits, hanjie.WithFilename("foo.yml"))
+
+			var puzzleError *errors.PuzzleError
+
+			assert.Nil(t, actual)
+			assert.ErrorIs(t, err, errors.ErrLimitExceeded)
+			assert.ErrorAs(t, err, &puzzleError)
+			assert.Equal(t, testDatum.field, puzzleError.Field)
+			assert.Equal(t, testDatum.position, puzzleError.Position)
+		})
+	}
+
+	t.Run("ErrorCausePuzzles", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := hanjie.Read(strings.NewReader(legacyString+legacyString), hanjie.WithLimits(0, 0, 0, 1, 0))
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrLimitExceeded)
+	})
+
+	t.Run("ErrorCauseBytes", func(t *testing.T) {
+		t.Parallel()
+
+		for _, format := range []string{hanjie.FormatYAML, hanjie.FormatJSON} {
+			input := expectedString
+			if format == hanjie.FormatJSON {
+				input = expectedJSONString
+			}
+
+			actual, err := hanjie.Read(strings.NewReader(input), hanjie.WithFormat(format), hanjie.WithLimits(0, 0, 0, 0, 100))
+
+			assert.Nil(t, actual)
+			assert.ErrorIs(t, err, errors.ErrLimitExceeded)
+		}
+	})
+
+	t.Run("ErrorCauseDecoderPuzzles", func(t *testing.T) {
+		t.Parallel()
+
+		decoder := hanjie.NewDecoder(strings.NewReader(legacyString+legacyString), hanjie.WithLimits(0, 0, 0, 1, 0))
+
+		actual, err := decoder.Decode()
+
+		assert.NoError(t, err)
+		assert.NotNil(t, actual)
+
+		actual, err = decoder.Decode()
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrLimitExceeded)
+	})
+
+	t.Run("ErrorCauseDecoderBytes", func(t *testing.T) {
+		t.Parallel()
+
+		decoder := hanjie.NewDecoder(strings.NewReader(legacyString), hanjie.WithLimits(0, 0, 0, 0, 100))
+
+		actual, err := decoder.Decode()
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrLimitExceeded)
+	})
+}
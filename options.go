@@ -6,6 +6,13 @@ import "github.com/alexeyco/hanjie/validator"
 type Options struct {
 	Validator      Validator
 	SkipValidation bool
+	KeepValid      bool
+	Filename       string
+	Format         string
+	Canonical      bool
+	Version        int
+	Strict         bool
+	Limits         Limits
 }
 
 // Option setter.
@@ -23,8 +30,61 @@ func SkipValidation(o *Options) {
 	o.SkipValidation = true
 }
 
+// KeepValid keeps valid puzzles and reports invalid ones instead of failing the whole set.
+func KeepValid(o *Options) {
+	o.KeepValid = true
+}
+
+// WithFilename sets the file name used in error positions.
+func WithFilename(name string) Option {
+	return func(o *Options) {
+		o.Filename = name
+	}
+}
+
+// WithFormat sets the name of the registered format to read or write, YAML is used by default.
+func WithFormat(name string) Option {
+	return func(o *Options) {
+		o.Format = name
+	}
+}
+
+// Canonical writes puzzles in the normal form, see tools.Normalize, so equal puzzles are written byte-identical.
+func Canonical(o *Options) {
+	o.Canonical = true
+}
+
+// WithVersion sets the YAML document format version to write, CurrentVersion is written by default.
+func WithVersion(version int) Option {
+	return func(o *Options) {
+		o.Version = version
+	}
+}
+
+// Strict rejects unknown fields and duplicate keys of YAML and JSON documents. Every offending key is reported
+// with its position in errors.ValidationError, keys of puzzle extensions are allowed, see ast.IsExtension.
+func Strict(o *Options) {
+	o.Strict = true
+}
+
+// WithLimits limits the size of puzzles and the input, zero means no limit. Use it reading untrusted input:
+// the input larger than maxBytes or puzzles exceeding the limits fail reading with errors.ErrLimitExceeded.
+func WithLimits(maxWidth, maxHeight, maxColors, maxPuzzles int, maxBytes int64) Option {
+	return func(o *Options) {
+		o.Limits = Limits{
+			MaxWidth:   maxWidth,
+			MaxHeight:  maxHeight,
+			MaxColors:  maxColors,
+			MaxPuzzles: maxPuzzles,
+			MaxBytes:   maxBytes,
+		}
+	}
+}
+
 func newOptions() Options {
 	return Options{
 		Validator: validator.New(),
+		Format:    FormatYAML,
+		Version:   CurrentVersion,
 	}
 }
@@ -8,16 +8,16 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-type validator struct{}
+type nopValidator struct{}
 
-func (v *validator) Validate(_ ast.PuzzleSet) error {
+func (v *nopValidator) Validate(_ ast.PuzzleSet) error {
 	return nil
 }
 
 func TestWithValidator(t *testing.T) {
 	t.Parallel()
 
-	v := &validator{}
+	v := &nopValidator{}
 	o := hanjie.Options{}
 
 	hanjie.WithValidator(v)(&o)
@@ -33,3 +33,66 @@ func TestSkipValidation(t *testing.T) {
 
 	assert.True(t, o.SkipValidation)
 }
+
+func TestKeepValid(t *testing.T) {
+	t.Parallel()
+
+	o := hanjie.Options{}
+	hanjie.KeepValid(&o)
+
+	assert.True(t, o.KeepValid)
+}
+
+func TestWithFilename(t *testing.T) {
+	t.Parallel()
+
+	o := hanjie.Options{}
+	hanjie.WithFilename("foo.yml")(&o)
+
+	assert.Equal(t, "foo.yml", o.Filename)
+}
+
+func TestWithFormat(t *testing.T) {
+	t.Parallel()
+
+	o := hanjie.Options{}
+	hanjie.WithFormat(hanjie.FormatJSON)(&o)
+
+	assert.Equal(t, hanjie.FormatJSON, o.Format)
+}
+
+func TestCanonical(t *testing.T) {
+	t.Parallel()
+
+	o := hanjie.Options{}
+	hanjie.Canonical(&o)
+
+	assert.True(t, o.Canonical)
+}
+
+func TestWithVersion(t *testing.T) {
+	t.Parallel()
+
+	o := hanjie.Options{}
+	hanjie.WithVersion(1)(&o)
+
+	assert.Equal(t, 1, o.Version)
+}
+
+func TestStrictOption(t *testing.T) {
+	t.Parallel()
+
+	o := hanjie.Options{}
+	hanjie.Strict(&o)
+
+	assert.True(t, o.Strict)
+}
+
+func TestWithLimitsOption(t *testing.T) {
+	t.Parallel()
+
+	o := hanjie.Options{}
+	hanjie.WithLimits(1, 2, 3, 4, 5)(&o)
+
+	assert.Equal(t, hanjie.Limits{MaxWidth: 1, MaxHeight: 2, MaxColors: 3, MaxPuzzles: 4, MaxBytes: 5}, o.Limits)
+}
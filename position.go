@@ -0,0 +1,211 @@
+package hanjie
+
+import (
+	goerrors "errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/alexeyco/hanjie/errors"
+)
+
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+// syntaxError returns decoding error positioned in the source file.
+func syntaxError(o Options, err error) error {
+	var syntaxError *errors.SyntaxError
+	if goerrors.As(err, &syntaxError) {
+		syntaxError.Position.File = o.Filename
+
+		return err
+	}
+
+	var typeError *yaml.TypeError
+	if !goerrors.As(err, &typeError) && !strings.HasPrefix(err.Error(), "yaml: ") {
+		return err
+	}
+
+	pos := errors.Position{File: o.Filename}
+	if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+		pos.Line, _ = strconv.Atoi(m[1])
+	}
+
+	return &errors.SyntaxError{
+		Position: pos,
+		Err:      err,
+	}
+}
+
+// locate sets source positions of puzzle errors, puzzle nodes are looked up by the puzzle index.
+func locate(o Options, puzzleNode func(index int) *yaml.Node, err error) {
+	var validationError errors.ValidationError
+	if !goerrors.As(err, &validationError) {
+		return
+	}
+
+	for _, e := range validationError {
+		var puzzleError *errors.PuzzleError
+		if !goerrors.As(e, &puzzleError) {
+			continue
+		}
+
+		node := puzzleNode(puzzleError.Index)
+		if node == nil {
+			continue
+		}
+
+		node = nodeAt(node, puzzleError.Field)
+
+		puzzleError.Position = errors.Position{
+			File:   o.Filename,
+			Line:   node.Line,
+			Column: node.Column,
+		}
+	}
+}
+
+// nodeAt returns the node of the puzzle field, e.g. clue.rows[3][1].count.
+// If the field can't be found, the closest existing parent is returned.
+func nodeAt(node *yaml.Node, field string) *yaml.Node {
+	node = resolve(node)
+
+	for _, key := range fieldKeys(field) {
+		next := child(node, key)
+		if next == nil {
+			if token := lineToken(node, key); token != nil {
+				node = token
+			}
+
+			break
+		}
+
+		node = next
+	}
+
+	return node
+}
+
+func child(node *yaml.Node, key string) *yaml.Node {
+	node = resolve(node)
+
+	switch node.Kind {
+	case yaml.SequenceNode:
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(node.Content) {
+			return nil
+		}
+
+		return resolve(node.Content[i])
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				return resolve(node.Content[i+1])
+			}
+		}
+	}
+
+	return nil
+}
+
+// lineToken returns position of the clue item in the line written in the compact notation, e.g. "2x 1r 3x".
+func lineToken(node *yaml.Node, key string) *yaml.Node {
+	index, err := strconv.Atoi(key)
+	if err != nil || node.Kind != yaml.ScalarNode {
+		return nil
+	}
+
+	column := node.Column
+	if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 {
+		column++
+	}
+
+	for offset := 0; offset < len(node.Value); {
+		if node.Value[offset] == ' ' || node.Value[offset] == '\t' {
+			offset++
+
+			continue
+		}
+
+		end := strings.IndexAny(node.Value[offset:], " \t")
+		if end < 0 {
+			end = len(node.Value) - offset
+		}
+
+		if !isZeroCount(node.Value[offset : offset+end]) {
+			if index == 0 {
+				return &yaml.Node{Line: node.Line, Column: column + offset}
+			}
+
+			index--
+		}
+
+		offset += end
+	}
+
+	return nil
+}
+
+func isZeroCount(token string) bool {
+	count := token[:len(token)-len(strings.TrimLeft(token, "0123456789"))]
+
+	return count != "" && strings.Trim(count, "0") == ""
+}
+
+func resolve(node *yaml.Node) *yaml.Node {
+	for node != nil {
+		switch {
+		case node.Kind == yaml.DocumentNode && len(node.Content) > 0:
+			node = node.Content[0]
+		case node.Kind == yaml.AliasNode && node.Alias != nil:
+			node = node.Alias
+		default:
+			return node
+		}
+	}
+
+	return node
+}
+
+// fieldKeys splits field path into keys, e.g. clue.rows[3] into clue, rows and 3.
+// Brackets hold either a sequence index or a mapping key, e.g. colors[.].
+func fieldKeys(field string) []string {
+	var keys []string
+
+	var key strings.Builder
+	for i := 0; i < len(field); i++ {
+		switch field[i] {
+		case '.':
+			if key.Len() > 0 {
+				keys = append(keys, key.String())
+				key.Reset()
+			}
+		case '[':
+			if key.Len() > 0 {
+				keys = append(keys, key.String())
+				key.Reset()
+			}
+
+			end := -1
+			if i+2 <= len(field) {
+				end = strings.IndexByte(field[i+2:], ']')
+			}
+
+			if end < 0 {
+				return append(keys, field[i+1:])
+			}
+
+			keys = append(keys, field[i+1:i+2+end])
+			i += 2 + end
+		default:
+			key.WriteByte(field[i])
+		}
+	}
+
+	if key.Len() > 0 {
+		keys = append(keys, key.String())
+	}
+
+	return keys
+}
@@ -0,0 +1,219 @@
+// Package schema contains JSON Schema of the puzzle format generated from ast types.
+package schema
+
+import (
+	// embed is used to ship the generated schema.
+	_ "embed"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/alexeyco/hanjie/ast"
+)
+
+const (
+	draft = "https://json-schema.org/draft/2020-12/schema"
+	id    = "https://github.com/alexeyco/hanjie/schema/puzzle.schema.json"
+	title = "Hanjie puzzle set"
+)
+
+// JSON is the published JSON Schema of the puzzle set document, it validates both JSON and YAML files.
+// Run "go test ./schema -update" to regenerate it after changing ast types.
+//
+//go:embed puzzle.schema.json
+var JSON []byte
+
+type node struct {
+	Schema               string           `json:"$schema,omitempty"`
+	ID                   string           `json:"$id,omitempty"`
+	Ref                  string           `json:"$ref,omitempty"`
+	Title                string           `json:"title,omitempty"`
+	Description          string           `json:"description,omitempty"`
+	Type                 string           `json:"type,omitempty"`
+	Pattern              string           `json:"pattern,omitempty"`
+	MinLength            int              `json:"minLength,omitempty"`
+	MaxLength            int              `json:"maxLength,omitempty"`
+	Properties           map[string]*node `json:"properties,omitempty"`
+	Required             []string         `json:"required,omitempty"`
+	PatternProperties    map[string]*node `json:"patternProperties,omitempty"`
+	PropertyNames        *node            `json:"propertyNames,omitempty"`
+	AdditionalProperties interface{}      `json:"additionalProperties,omitempty"`
+	Items                *node            `json:"items,omitempty"`
+	OneOf                []*node          `json:"oneOf,omitempty"`
+	Defs                 map[string]*node `json:"$defs,omitempty"`
+}
+
+// override returns schema of types with custom text encoding.
+func (g *generator) override(t reflect.Type) (*node, bool) {
+	switch t {
+	case reflect.TypeOf(ast.Char(0)):
+		return &node{
+			Description: "Single character identifying a color.",
+			Type:        "string",
+			MinLength:   1,
+			MaxLength:   1,
+		}, true
+	case reflect.TypeOf(ast.Color{}):
+		return &node{
+			Description: "Color in #rgb or #rrggbb hex form.",
+			Type:        "string",
+			Pattern:     "^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$",
+		}, true
+	case reflect.TypeOf(ast.Line{}):
+		return &node{
+			Description: `Clue line, either a list of items or counts followed by color chars, e.g. "2x 1r 3x". ` +
+				`Colors may be omitted in single-color puzzles, e.g. "2 1 3".`,
+			OneOf: []*node{
+				{Type: "string", Pattern: `^\s*(\d+\S?(\s+\d+\S?)*)?\s*$`},
+				{Type: "array", Items: g.schema(reflect.TypeOf(ast.Item{}))},
+			},
+		}, true
+	case reflect.TypeOf(ast.Goal{}):
+		return &node{
+			Description: "Rows of the picture, every row is either a string of chars or a list of chars.",
+			Type:        "array",
+			Items: &node{
+				OneOf: []*node{
+					{Type: "string"},
+					{Type: "array", Items: g.schema(reflect.TypeOf(ast.Char(0)))},
+				},
+			},
+		}, true
+	}
+
+	return nil, false
+}
+
+// Generate returns JSON Schema of ast.PuzzleSet.
+func Generate() ([]byte, error) {
+	g := generator{
+		defs: map[string]*node{},
+	}
+
+	puzzles := g.schema(reflect.TypeOf(ast.PuzzleSet{}))
+
+	// Documents of version 2 and later are collections with the version of the document format.
+	collection := g.schema(reflect.TypeOf(ast.Collection{}))
+	document := g.defs["Collection"]
+	document.Description = "Document of version 2 and later, revision is the version of the collection itself."
+	document.Properties["version"] = &node{Description: "Version of the document format.", Type: "integer"}
+	document.Required = append([]string{"version"}, document.Required...)
+
+	root := &node{
+		OneOf: []*node{puzzles, collection},
+	}
+	root.Schema = draft
+	root.ID = id
+	root.Title = title
+	root.Defs = g.defs
+
+	b, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '\n'), nil
+}
+
+type generator struct {
+	defs map[string]*node
+}
+
+// schema returns a reference for named types and an inline schema for others.
+func (g *generator) schema(t reflect.Type) *node {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Name() == "" || t.PkgPath() == "" {
+		return g.inline(t)
+	}
+
+	if _, ok := g.defs[t.Name()]; !ok {
+		g.defs[t.Name()] = &node{}
+		*g.defs[t.Name()] = *g.inline(t)
+	}
+
+	return &node{Ref: "#/$defs/" + t.Name()}
+}
+
+func (g *generator) inline(t reflect.Type) *node {
+	if n, ok := g.override(t); ok {
+		return n
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return g.object(t)
+	case reflect.Slice, reflect.Array:
+		return &node{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &node{
+			Type:                 "object",
+			PropertyNames:        g.schema(t.Key()),
+			AdditionalProperties: g.schema(t.Elem()),
+		}
+	case reflect.String:
+		return &node{Type: "string"}
+	case reflect.Bool:
+		return &node{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &node{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &node{Type: "number"}
+	}
+
+	return &node{}
+}
+
+func (g *generator) object(t reflect.Type) *node {
+	n := &node{
+		Type:                 "object",
+		Properties:           map[string]*node{},
+		AdditionalProperties: false,
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if field.Type == reflect.TypeOf(ast.Puzzle{}.Extensions) && field.Name == "Extensions" {
+			n.PatternProperties = map[string]*node{
+				"^" + ast.ExtensionPrefix: {Description: "Vendor-specific extension."},
+			}
+
+			continue
+		}
+
+		tag := strings.Split(field.Tag.Get("json"), ",")
+		if tag[0] == "-" {
+			continue
+		}
+
+		name := tag[0]
+		if name == "" {
+			name = field.Name
+		}
+
+		n.Properties[name] = g.schema(field.Type)
+
+		if !hasOption(tag[1:], "omitempty") {
+			n.Required = append(n.Required, name)
+		}
+	}
+
+	return n
+}
+
+func hasOption(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+
+	return false
+}
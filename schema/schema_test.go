@@ -0,0 +1,29 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/alexeyco/hanjie/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "regenerate puzzle.schema.json")
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	actual, err := schema.Generate()
+	assert.NoError(t, err)
+	assert.True(t, json.Valid(actual))
+
+	if *update {
+		assert.NoError(t, os.WriteFile("puzzle.schema.json", actual, 0o644))
+
+		return
+	}
+
+	assert.Equal(t, string(schema.JSON), string(actual), `schema is outdated, run "go test ./schema -update"`)
+}
@@ -0,0 +1,305 @@
+// Package solver solves puzzles by their clues.
+package solver
+
+import (
+	"fmt"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+)
+
+// Unknown marks cells which color isn't known.
+const Unknown = ast.Char(0)
+
+// maxColors is the number of colors a domain holds, background included.
+const maxColors = 64
+
+// domain is a set of colors the cell may have, a bit per color of the palette.
+type domain uint64
+
+// item of the clue line with the color bit.
+type item struct {
+	color domain
+	count int
+}
+
+// SolveLine returns cells of the line forced by the clue line and the known cells, other cells are Unknown.
+// Items should have colors, items without color, e.g. parsed from "2 1", give errors.ErrSyntax.
+// Adjacent items of the same color are separated by the background, items of different colors may touch.
+// If no arrangement of the items matches the known cells, errors.ErrContradiction is returned.
+func SolveLine(line ast.Line, cells []ast.Char, background ast.Char) ([]ast.Char, error) {
+	p := palette{background}
+
+	items, err := p.items(line)
+	if err != nil {
+		return nil, err
+	}
+
+	all := p.all()
+
+	domains := make([]domain, len(cells))
+	for i, ch := range cells {
+		if ch == Unknown {
+			domains[i] = all
+
+			continue
+		}
+
+		color, ok := p.lookup(ch)
+		if !ok {
+			return nil, fmt.Errorf(`%w: cell %d has color "%s" absent in the line`, errors.ErrContradiction, i, string(ch))
+		}
+
+		domains[i] = color
+	}
+
+	solved, err := solveLine(items, domains, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ast.Char, len(solved))
+	for i, d := range solved {
+		result[i] = p.char(d)
+	}
+
+	return result, nil
+}
+
+// palette holds chars by their bits, the background is the first one.
+type palette []ast.Char
+
+// items returns items of the line with positive counts, colors are added to the palette.
+func (p *palette) items(line ast.Line) ([]item, error) {
+	items := make([]item, 0, len(line))
+	for _, it := range line {
+		if it.Count <= 0 {
+			continue
+		}
+
+		// ast.ImplicitChar equals Unknown, so the color of the item would be taken for unknown cells.
+		if it.Color == ast.ImplicitChar {
+			return nil, fmt.Errorf("%w: item without color, resolve it to the foreground color", errors.ErrSyntax)
+		}
+
+		if it.Color == (*p)[0] {
+			return nil, fmt.Errorf(`%w: item of the background color "%s"`, errors.ErrContradiction, string(it.Color))
+		}
+
+		color, err := p.bit(it.Color)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item{color: color, count: it.Count})
+	}
+
+	return items, nil
+}
+
+func (p *palette) bit(ch ast.Char) (domain, error) {
+	if color, ok := p.lookup(ch); ok {
+		return color, nil
+	}
+
+	if len(*p) == maxColors {
+		return 0, fmt.Errorf("%w: more than %d colors", errors.ErrLimitExceeded, maxColors)
+	}
+
+	*p = append(*p, ch)
+
+	return domain(1) << (len(*p) - 1), nil
+}
+
+func (p palette) lookup(ch ast.Char) (domain, bool) {
+	for i, c := range p {
+		if c == ch {
+			return domain(1) << i, true
+		}
+	}
+
+	return 0, false
+}
+
+func (p palette) all() domain {
+	if len(p) == maxColors {
+		return ^domain(0)
+	}
+
+	return domain(1)<<len(p) - 1
+}
+
+// char returns the only color of the domain or Unknown.
+func (p palette) char(d domain) ast.Char {
+	if d == 0 || d&(d-1) != 0 {
+		return Unknown
+	}
+
+	for i := range p {
+		if d == domain(1)<<i {
+			return p[i]
+		}
+	}
+
+	return Unknown
+}
+
+// solveLine narrows domains of the cells to the colors the cells have in any arrangement of the items.
+//
+// Every item is a block of cells of its color followed by a background cell if the next item has the same color.
+// fits[j][i] tells if blocks j..k-1 fit cells i..n-1 and fitsBefore[j][i] if blocks 0..j-1 fit cells 0..i-1,
+// cells not covered by blocks are background. A block may start at s if blocks before it fit cells before s
+// and blocks after it fit cells after the block.
+func solveLine(items []item, cells []domain, background domain) ([]domain, error) {
+	n, k := len(cells), len(items)
+
+	// Counts come from untrusted clues, so they are checked against the line length before any arithmetic.
+	blocks := make([]int, k)
+	length := 0
+
+	for j, it := range items {
+		if it.count > n {
+			return nil, fmt.Errorf("%w: item of %d cells doesn't fit the line of %d", errors.ErrContradiction, it.count, n)
+		}
+
+		blocks[j] = it.count
+		if j+1 < k && items[j+1].color == it.color {
+			blocks[j]++
+		}
+
+		if length += blocks[j]; length > n {
+			return nil, fmt.Errorf("%w: items don't fit the line", errors.ErrContradiction)
+		}
+	}
+
+	// allowed[c][i] counts cells before i allowing the c-th color of the line, the background is the first one,
+	// so the range check takes constant time.
+	lineColors := []domain{background}
+	index := make([]int, k)
+
+	for j, it := range items {
+		index[j] = len(lineColors)
+		for c, color := range lineColors {
+			if color == it.color {
+				index[j] = c
+
+				break
+			}
+		}
+
+		if index[j] == len(lineColors) {
+			lineColors = append(lineColors, it.color)
+		}
+	}
+
+	allowed := make([][]int, len(lineColors))
+	for c, color := range lineColors {
+		allowed[c] = make([]int, n+1)
+		for i, d := range cells {
+			allowed[c][i+1] = allowed[c][i]
+			if d&color != 0 {
+				allowed[c][i+1]++
+			}
+		}
+	}
+
+	allows := func(c, from, to int) bool {
+		return allowed[c][to]-allowed[c][from] == to-from
+	}
+
+	// placeable tells if block j fits cells starting at s.
+	placeable := func(j, s int) bool {
+		end := s + items[j].count
+
+		return s+blocks[j] <= n && allows(index[j], s, end) && allows(0, end, s+blocks[j])
+	}
+
+	fitsBefore := make([][]bool, k+1)
+	for j := range fitsBefore {
+		fitsBefore[j] = make([]bool, n+1)
+	}
+
+	fitsBefore[0][0] = true
+
+	for j := 0; j <= k; j++ {
+		for i := 1; i <= n; i++ {
+			if fitsBefore[j][i-1] && allows(0, i-1, i) {
+				fitsBefore[j][i] = true
+			}
+
+			if j > 0 && i >= blocks[j-1] && fitsBefore[j-1][i-blocks[j-1]] && placeable(j-1, i-blocks[j-1]) {
+				fitsBefore[j][i] = true
+			}
+		}
+	}
+
+	if !fitsBefore[k][n] {
+		return nil, fmt.Errorf("%w: items don't fit the line", errors.ErrContradiction)
+	}
+
+	fits := make([][]bool, k+1)
+	for j := range fits {
+		fits[j] = make([]bool, n+1)
+	}
+
+	fits[k][n] = true
+
+	for j := k; j >= 0; j-- {
+		for i := n - 1; i >= 0; i-- {
+			if fits[j][i+1] && allows(0, i, i+1) {
+				fits[j][i] = true
+			}
+
+			if j < k && i+blocks[j] <= n && fits[j+1][i+blocks[j]] && placeable(j, i) {
+				fits[j][i] = true
+			}
+		}
+	}
+
+	// colored[c] marks ranges of cells that may have the c-th color as a difference array.
+	colored := make([][]int, len(lineColors))
+	for c := range colored {
+		colored[c] = make([]int, n+1)
+	}
+
+	mark := func(c, from, to int) {
+		colored[c][from]++
+		colored[c][to]--
+	}
+
+	for i := 0; i < n; i++ {
+		for j := 0; j <= k; j++ {
+			if fitsBefore[j][i] && fits[j][i+1] && allows(0, i, i+1) {
+				mark(0, i, i+1)
+
+				break
+			}
+		}
+	}
+
+	for j := 0; j < k; j++ {
+		for s := 0; s+blocks[j] <= n; s++ {
+			if fitsBefore[j][s] && fits[j+1][s+blocks[j]] && placeable(j, s) {
+				mark(index[j], s, s+items[j].count)
+
+				if blocks[j] > items[j].count {
+					mark(0, s+items[j].count, s+blocks[j])
+				}
+			}
+		}
+	}
+
+	solved := make([]domain, n)
+
+	for c, diff := range colored {
+		count := 0
+		for i := 0; i < n; i++ {
+			count += diff[i]
+			if count > 0 {
+				solved[i] |= lineColors[c]
+			}
+		}
+	}
+
+	return solved, nil
+}
@@ -0,0 +1,224 @@
+package solver_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/solver"
+	"github.com/stretchr/testify/assert"
+)
+
+// cells returns cells of the string, "?" is unknown.
+func cells(s string) []ast.Char {
+	result := make([]ast.Char, 0, len(s))
+	for _, r := range s {
+		if r == '?' {
+			result = append(result, solver.Unknown)
+		} else {
+			result = append(result, ast.Char(r))
+		}
+	}
+
+	return result
+}
+
+func TestSolveLine(t *testing.T) {
+	t.Parallel()
+
+	testData := [...]struct {
+		name     string
+		line     string
+		cells    string
+		expected string
+	}{
+		{name: "Empty", line: "", cells: "???", expected: "..."},
+		{name: "Full", line: "3", cells: "???", expected: "xxx"},
+		{name: "Overlap", line: "3", cells: "?????", expected: "??x??"},
+		{name: "SameColorsGap", line: "1 1", cells: "???", expected: "x.x"},
+		{name: "DifferentColorsTouch", line: "1x 1r", cells: "??", expected: "xr"},
+		{name: "DifferentColorsMayTouch", line: "2x 1r", cells: "????", expected: "?x??"},
+		{name: "KnownCells", line: "1", cells: "?x?", expected: ".x."},
+		{name: "KnownBackground", line: "2", cells: "?.??", expected: "..xx"},
+		{name: "Unsolved", line: "1", cells: "??", expected: "??"},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			line, err := ast.ParseLine(testDatum.line)
+			assert.NoError(t, err)
+
+			for i := range line {
+				if line[i].Color == ast.ImplicitChar {
+					line[i].Color = 'x'
+				}
+			}
+
+			actual, err := solver.SolveLine(line, cells(testDatum.cells), '.')
+
+			assert.NoError(t, err)
+			assert.Equal(t, cells(testDatum.expected), actual)
+		})
+	}
+
+	t.Run("ErrorImplicitColor", func(t *testing.T) {
+		t.Parallel()
+
+		line, err := ast.ParseLine("3")
+		assert.NoError(t, err)
+
+		actual, err := solver.SolveLine(line, make([]ast.Char, 3), '.')
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrSyntax)
+	})
+
+	maxInt := int(^uint(0) >> 1)
+
+	errorData := [...]struct {
+		name  string
+		line  ast.Line
+		cells string
+	}{
+		{name: "TooLong", line: ast.Line{{Color: 'x', Count: 3}}, cells: "??"},
+		{name: "SameColorsNoGap", line: ast.Line{{Color: 'x', Count: 1}, {Color: 'x', Count: 1}}, cells: "??"},
+		{name: "KnownBackground", line: ast.Line{{Color: 'x', Count: 1}}, cells: "..."},
+		{name: "AbsentColor", line: ast.Line{{Color: 'x', Count: 1}}, cells: "r??"},
+		{name: "BackgroundItem", line: ast.Line{{Color: '.', Count: 1}}, cells: "??"},
+		{name: "HugeCount", line: ast.Line{{Color: 'x', Count: maxInt}}, cells: "???"},
+		{name: "HugeCountSameColor", line: ast.Line{{Color: 'x', Count: maxInt}, {Color: 'x', Count: 1}}, cells: "???"},
+		{name: "HugeSum", line: ast.Line{{Color: 'x', Count: 3}, {Color: 'r', Count: 3}, {Color: 'x', Count: 3}}, cells: "?????"},
+	}
+
+	for _, testDatum := range errorData {
+		testDatum := testDatum
+
+		t.Run("Error"+testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := solver.SolveLine(testDatum.line, cells(testDatum.cells), '.')
+
+			assert.Nil(t, actual)
+			assert.ErrorIs(t, err, errors.ErrContradiction)
+		})
+	}
+}
+
+// TestSolveLine_BruteForce compares the solver with all arrangements of random short lines.
+func TestSolveLine_BruteForce(t *testing.T) {
+	t.Parallel()
+
+	colors := []ast.Char{'.', 'x', 'r'}
+	random := rand.New(rand.NewSource(1))
+
+	for n := 0; n < 500; n++ {
+		width := 1 + random.Intn(7)
+
+		row := make([]ast.Char, width)
+		for i := range row {
+			row[i] = colors[random.Intn(len(colors))]
+		}
+
+		line := lineOf(row)
+
+		known := make([]ast.Char, width)
+		for i := range known {
+			known[i] = solver.Unknown
+			if random.Intn(3) == 0 {
+				known[i] = colors[random.Intn(len(colors))]
+			}
+		}
+
+		expected, ok := bruteForce(line, known, colors)
+
+		actual, err := solver.SolveLine(line, known, '.')
+		if !ok {
+			assert.ErrorIs(t, err, errors.ErrContradiction, "line %v, cells %q", line, string(known))
+
+			continue
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "line %v, cells %q", line, string(known))
+	}
+}
+
+func lineOf(row []ast.Char) ast.Line {
+	var line ast.Line
+
+	for i := 0; i < len(row); {
+		n := 1
+		for i+n < len(row) && row[i+n] == row[i] {
+			n++
+		}
+
+		if row[i] != '.' {
+			line = append(line, ast.Item{Color: row[i], Count: n})
+		}
+
+		i += n
+	}
+
+	return line
+}
+
+// bruteForce returns cells equal in all rows matching the line and the known cells.
+func bruteForce(line ast.Line, known []ast.Char, colors []ast.Char) ([]ast.Char, bool) {
+	width := len(known)
+	row := make([]ast.Char, width)
+
+	var result []ast.Char
+
+	var walk func(i int)
+	walk = func(i int) {
+		if i == width {
+			if !equalLines(lineOf(row), line) {
+				return
+			}
+
+			if result == nil {
+				result = append([]ast.Char{}, row...)
+
+				return
+			}
+
+			for c := range row {
+				if result[c] != row[c] {
+					result[c] = solver.Unknown
+				}
+			}
+
+			return
+		}
+
+		for _, color := range colors {
+			if known[i] == solver.Unknown || known[i] == color {
+				row[i] = color
+				walk(i + 1)
+			}
+		}
+	}
+
+	walk(0)
+
+	return result, result != nil
+}
+
+func equalLines(a, b ast.Line) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
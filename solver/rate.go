@@ -0,0 +1,79 @@
+package solver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+)
+
+// Difficulty labels by the hardest technique needed to solve the puzzle.
+const (
+	// Easy puzzles are solved line by line.
+	Easy = "easy"
+	// Medium puzzles need probing.
+	Medium = "medium"
+	// Hard puzzles need guessing.
+	Hard = "hard"
+)
+
+// Weights of the techniques in the score.
+const (
+	lineWeight  = 1
+	probeWeight = 10
+	guessWeight = 100
+)
+
+// Rate rates the puzzle by the deduction techniques the solver needs to solve it, see Stats.
+// The score is the number of lines solved plus 10 per probe and 100 per guess, so puzzles of the same label
+// are ordered by the amount of work. The result may be stored in Puzzle.Difficulty, see FillDifficulty.
+// Puzzles without solution give errors.ErrUnsolvable.
+func Rate(puzzle ast.Puzzle) (ast.Difficulty, error) {
+	return RateContext(context.Background(), puzzle)
+}
+
+// RateContext rates the puzzle the same way Rate does. If ctx is done before the puzzle is solved,
+// the ctx error is returned.
+func RateContext(ctx context.Context, puzzle ast.Puzzle) (ast.Difficulty, error) {
+	result, err := Solve(ctx, puzzle)
+	if err != nil {
+		return ast.Difficulty{}, err
+	}
+
+	if result.Status == Contradiction {
+		return ast.Difficulty{}, fmt.Errorf("%w: %s", errors.ErrUnsolvable, result.Contradiction)
+	}
+
+	stats := result.Stats
+
+	label := Easy
+
+	switch {
+	case stats.Guesses > 0:
+		label = Hard
+	case stats.Probes > 0:
+		label = Medium
+	}
+
+	return ast.Difficulty{
+		Score:   stats.Lines*lineWeight + stats.Probes*probeWeight + stats.Guesses*guessWeight,
+		Label:   label,
+		Lines:   stats.Lines,
+		Probes:  stats.Probes,
+		Guesses: stats.Guesses,
+	}, nil
+}
+
+// FillDifficulty rates the puzzle with RateContext and stores the rating in Puzzle.Difficulty.
+// The puzzle is left unchanged on error.
+func FillDifficulty(ctx context.Context, puzzle *ast.Puzzle) error {
+	difficulty, err := RateContext(ctx, *puzzle)
+	if err != nil {
+		return err
+	}
+
+	puzzle.Difficulty = &difficulty
+
+	return nil
+}
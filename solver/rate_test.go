@@ -0,0 +1,102 @@
+package solver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/solver"
+	"github.com/alexeyco/hanjie/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRate(t *testing.T) {
+	t.Parallel()
+
+	testData := [...]struct {
+		name     string
+		goal     ast.Goal
+		expected ast.Difficulty
+	}{
+		{
+			name:     "Easy",
+			goal:     goal(".x.", "xxx", ".x."),
+			expected: ast.Difficulty{Score: 4, Label: solver.Easy, Lines: 4},
+		},
+		{
+			name:     "Medium",
+			goal:     goal("x..x", ".xx.", "....", "...x"),
+			expected: ast.Difficulty{Score: 19, Label: solver.Medium, Lines: 9, Probes: 1},
+		},
+		{
+			name:     "Hard",
+			goal:     goal("xx.", "x.x", ".xx"),
+			expected: ast.Difficulty{Score: 103, Label: solver.Hard, Lines: 3, Guesses: 1},
+		},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := solver.Rate(ast.Puzzle{
+				Background: ast.Char('.'),
+				Clue:       tools.GoalToClue(testDatum.goal, ast.Char('.')),
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, testDatum.expected, actual)
+		})
+	}
+
+	t.Run("ErrorCauseUnsolvable", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := solver.Rate(puzzle(t, []string{"1", "1"}, []string{"3"}))
+
+		assert.Equal(t, ast.Difficulty{}, actual)
+		assert.ErrorIs(t, err, errors.ErrUnsolvable)
+		assert.EqualError(t, err, "clue has no solution: clue.rows[0]")
+	})
+}
+
+func TestRateContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	actual, err := solver.RateContext(ctx, puzzle(t, []string{"2", "1 1", "2"}, []string{"2", "1 1", "2"}))
+
+	assert.Equal(t, ast.Difficulty{}, actual)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestFillDifficulty(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		p := puzzle(t, []string{"1", "3", "1"}, []string{"1", "3", "1"})
+
+		err := solver.FillDifficulty(context.Background(), &p)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &ast.Difficulty{Score: 4, Label: solver.Easy, Lines: 4}, p.Difficulty)
+	})
+
+	t.Run("ErrorCauseUnsolvable", func(t *testing.T) {
+		t.Parallel()
+
+		p := puzzle(t, []string{"1", "1"}, []string{"3"})
+
+		err := solver.FillDifficulty(context.Background(), &p)
+
+		assert.ErrorIs(t, err, errors.ErrUnsolvable)
+		assert.Nil(t, p.Difficulty)
+	})
+}
@@ -0,0 +1,508 @@
+package solver
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/tools"
+)
+
+// Status of the solution.
+type Status int
+
+const (
+	// Partial means the solver stopped before solving every cell, see Solve.
+	Partial Status = iota
+	// Solved means every cell is solved.
+	Solved
+	// Contradiction means the clue has no solution.
+	Contradiction
+)
+
+// String returns the status name.
+func (s Status) String() string {
+	switch s {
+	case Partial:
+		return "partial"
+	case Solved:
+		return "solved"
+	case Contradiction:
+		return "contradiction"
+	}
+
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// Line of the puzzle clue.
+type Line struct {
+	Column bool
+	Index  int
+}
+
+// String returns the field of the line in the puzzle, e.g. "clue.rows[3]".
+func (l Line) String() string {
+	if l.Column {
+		return fmt.Sprintf("clue.columns[%d]", l.Index)
+	}
+
+	return fmt.Sprintf("clue.rows[%d]", l.Index)
+}
+
+// Stats counts the deductions the solver made by technique.
+type Stats struct {
+	// Lines is the number of single lines solved, which gave new cells.
+	Lines int
+	// Probes is the number of cells solved by probing their colors against both lines of the cell.
+	Probes int
+	// Guesses is the number of colors tried by backtracking.
+	Guesses int
+}
+
+// Result of solving the puzzle.
+type Result struct {
+	// Goal holds the solved cells, other cells are Unknown.
+	Goal   ast.Goal
+	Status Status
+	Stats  Stats
+	// Contradiction is the line where the contradiction was found if the status is Contradiction.
+	Contradiction *Line
+}
+
+// Solve solves the puzzle by its clue, the goal of the puzzle is ignored.
+//
+// Lines are solved one by one until no line gives new cells, then colors of unsolved cells are probed,
+// and the rest is solved by backtracking. The first solution found is returned, it may be not the only one.
+// If ctx is done before the puzzle is solved, the cells solved so far are returned with Partial status
+// along with the ctx error.
+func Solve(ctx context.Context, puzzle ast.Puzzle) (*Result, error) {
+	s, err := newSolver(ctx, puzzle)
+	if err != nil {
+		return nil, err
+	}
+
+	cells := s.cells()
+	result := &Result{}
+
+	line, err := s.deduce(cells, &result.Stats)
+	if err == nil && line == nil {
+		var solution []domain
+
+		_, line, err = s.search(cells, &result.Stats, func(found []domain) bool {
+			solution = found
+
+			return false
+		})
+
+		if solution != nil {
+			cells, line = solution, nil
+		}
+	}
+
+	switch {
+	case err != nil:
+		result.Status = Partial
+	case line != nil:
+		result.Status = Contradiction
+		result.Contradiction = line
+	default:
+		result.Status = Solved
+	}
+
+	if result.Status != Contradiction {
+		result.Goal = s.goal(cells)
+	}
+
+	if err != nil && !goerrors.Is(err, ctx.Err()) {
+		return nil, err
+	}
+
+	return result, err
+}
+
+// Solutions returns solutions of the puzzle by its clue, the search stops after limit solutions are found,
+// so limit 2 tells if the solution is unique. The goal of the puzzle is ignored.
+// If the clue has no solution, *ContradictionError is returned.
+// If ctx is done before the search is over, the solutions found so far are returned along with the ctx error.
+func Solutions(ctx context.Context, puzzle ast.Puzzle, limit int) ([]ast.Goal, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	s, err := newSolver(ctx, puzzle)
+	if err != nil {
+		return nil, err
+	}
+
+	cells := s.cells()
+
+	var stats Stats
+
+	line, err := s.deduce(cells, &stats)
+	if err != nil {
+		return nil, err
+	}
+
+	var solutions []ast.Goal
+
+	if line == nil {
+		_, line, err = s.search(cells, &stats, func(found []domain) bool {
+			solutions = append(solutions, s.goal(found))
+
+			return len(solutions) < limit
+		})
+	}
+
+	if err == nil && len(solutions) == 0 {
+		return nil, &ContradictionError{Line: *line}
+	}
+
+	return solutions, err
+}
+
+// ContradictionError reports the line where the solver found that the clue has no solution.
+// It always matches errors.ErrContradiction.
+type ContradictionError struct {
+	Line Line
+}
+
+// Error returns contradiction error message.
+func (e *ContradictionError) Error() string {
+	return errors.ErrContradiction.Error() + " in " + e.Line.String()
+}
+
+// Is reports whether target is errors.ErrContradiction.
+func (e *ContradictionError) Is(target error) bool {
+	return target == errors.ErrContradiction
+}
+
+type solver struct {
+	ctx     context.Context
+	palette palette
+	width   int
+	height  int
+	rows    [][]item
+	columns [][]item
+	// invalid is the line with items which can't be placed in any line, e.g. of the background color.
+	invalid *Line
+}
+
+func newSolver(ctx context.Context, puzzle ast.Puzzle) (*solver, error) {
+	s := &solver{
+		ctx:     ctx,
+		palette: palette{puzzle.Background},
+		width:   len(puzzle.Clue.Columns),
+		height:  len(puzzle.Clue.Rows),
+	}
+
+	var err error
+	if s.rows, err = s.items(puzzle, puzzle.Clue.Rows, false); err != nil {
+		return nil, err
+	}
+
+	if s.columns, err = s.items(puzzle, puzzle.Clue.Columns, true); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// items returns items of the lines, items without color get the foreground color of the puzzle.
+func (s *solver) items(puzzle ast.Puzzle, lines []ast.Line, column bool) ([][]item, error) {
+	result := make([][]item, len(lines))
+
+	for i, line := range lines {
+		resolved := line
+
+		for j := range line {
+			if line[j].Color != ast.ImplicitChar {
+				continue
+			}
+
+			foreground, err := tools.Foreground(puzzle)
+			if err != nil {
+				return nil, err
+			}
+
+			resolved = append(ast.Line{}, line...)
+			for k := range resolved {
+				if resolved[k].Color == ast.ImplicitChar {
+					resolved[k].Color = foreground
+				}
+			}
+
+			break
+		}
+
+		items, err := s.palette.items(resolved)
+		if goerrors.Is(err, errors.ErrContradiction) && s.invalid == nil {
+			s.invalid = &Line{Column: column, Index: i}
+
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = items
+	}
+
+	return result, nil
+}
+
+// cells returns cells of the grid allowing every color, row by row.
+func (s *solver) cells() []domain {
+	cells := make([]domain, s.width*s.height)
+
+	all := s.palette.all()
+	for i := range cells {
+		cells[i] = all
+	}
+
+	return cells
+}
+
+// lines returns every line of the puzzle.
+func (s *solver) lines() []Line {
+	lines := make([]Line, 0, s.width+s.height)
+	for i := 0; i < s.height; i++ {
+		lines = append(lines, Line{Index: i})
+	}
+
+	for i := 0; i < s.width; i++ {
+		lines = append(lines, Line{Column: true, Index: i})
+	}
+
+	return lines
+}
+
+// crossing returns lines crossing at the cell.
+func (s *solver) crossing(cell int) []Line {
+	return []Line{{Index: cell / s.width}, {Column: true, Index: cell % s.width}}
+}
+
+// propagate solves the lines until no line gives new cells, solved lines are counted by progress.
+// The line where the contradiction is found is returned.
+func (s *solver) propagate(cells []domain, queue []Line, progress *int) (*Line, error) {
+	if s.invalid != nil {
+		return s.invalid, nil
+	}
+
+	queued := make([]bool, s.width+s.height)
+	for _, line := range queue {
+		queued[s.id(line)] = true
+	}
+
+	for len(queue) > 0 {
+		if err := s.ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		line := queue[0]
+		queue = queue[1:]
+		queued[s.id(line)] = false
+
+		index, items := s.line(line)
+
+		domains := make([]domain, len(index))
+		for i, cell := range index {
+			domains[i] = cells[cell]
+		}
+
+		solved, err := solveLine(items, domains, 1)
+		if goerrors.Is(err, errors.ErrContradiction) {
+			return &line, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		changed := false
+
+		for i, cell := range index {
+			if solved[i] == cells[cell] {
+				continue
+			}
+
+			cells[cell] = solved[i]
+			changed = true
+
+			crossing := Line{Column: true, Index: cell % s.width}
+			if line.Column {
+				crossing = Line{Index: cell / s.width}
+			}
+
+			if !queued[s.id(crossing)] {
+				queued[s.id(crossing)] = true
+				queue = append(queue, crossing)
+			}
+		}
+
+		if changed && progress != nil {
+			*progress++
+		}
+	}
+
+	return nil, nil
+}
+
+// id returns the number of the line, rows go first.
+func (s *solver) id(line Line) int {
+	if line.Column {
+		return s.height + line.Index
+	}
+
+	return line.Index
+}
+
+// line returns indexes of the cells of the line and its items.
+func (s *solver) line(line Line) ([]int, []item) {
+	if line.Column {
+		index := make([]int, s.height)
+		for i := range index {
+			index[i] = i*s.width + line.Index
+		}
+
+		return index, s.columns[line.Index]
+	}
+
+	index := make([]int, s.width)
+	for i := range index {
+		index[i] = line.Index*s.width + i
+	}
+
+	return index, s.rows[line.Index]
+}
+
+// probe tries every color of unsolved cells, colors which lead to contradictions are removed.
+// Probing repeats until no cell is solved.
+func (s *solver) probe(cells []domain, stats *Stats) (*Line, error) {
+	for progress := true; progress; {
+		progress = false
+
+		for cell := range cells {
+			if bits.OnesCount64(uint64(cells[cell])) < 2 {
+				continue
+			}
+
+			var contradiction *Line
+
+			for _, color := range colors(cells[cell]) {
+				probed := append([]domain(nil), cells...)
+				probed[cell] = color
+
+				line, err := s.propagate(probed, s.crossing(cell), nil)
+				if err != nil {
+					return nil, err
+				}
+
+				if line != nil {
+					cells[cell] &^= color
+					contradiction = line
+				}
+			}
+
+			if contradiction == nil {
+				continue
+			}
+
+			if cells[cell] == 0 {
+				return contradiction, nil
+			}
+
+			stats.Probes++
+			progress = true
+
+			line, err := s.propagate(cells, s.crossing(cell), &stats.Lines)
+			if line != nil || err != nil {
+				return line, err
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// deduce solves the cells without guessing: by lines, then by probing.
+func (s *solver) deduce(cells []domain, stats *Stats) (*Line, error) {
+	line, err := s.propagate(cells, s.lines(), &stats.Lines)
+	if line != nil || err != nil {
+		return line, err
+	}
+
+	return s.probe(cells, stats)
+}
+
+// search solves the cells by backtracking, guessing the cell with the fewest colors first.
+// Every solution is passed to found, the search stops when found returns false and the result is false then.
+// If no guess gives a solution, the line of the last contradiction is returned.
+func (s *solver) search(cells []domain, stats *Stats, found func([]domain) bool) (bool, *Line, error) {
+	cell, fewest := -1, maxColors+1
+
+	for i, d := range cells {
+		if n := bits.OnesCount64(uint64(d)); n > 1 && n < fewest {
+			cell, fewest = i, n
+		}
+	}
+
+	if cell < 0 {
+		return found(cells), nil, nil
+	}
+
+	var contradiction *Line
+
+	for _, color := range colors(cells[cell]) {
+		stats.Guesses++
+
+		guessed := append([]domain(nil), cells...)
+		guessed[cell] = color
+
+		line, err := s.propagate(guessed, s.crossing(cell), nil)
+		if err != nil {
+			return false, nil, err
+		}
+
+		if line == nil {
+			var more bool
+			if more, line, err = s.search(guessed, stats, found); !more || err != nil {
+				return false, nil, err
+			}
+		}
+
+		if line != nil {
+			contradiction = line
+		}
+	}
+
+	return true, contradiction, nil
+}
+
+// goal returns the goal of the cells, unsolved cells are Unknown.
+func (s *solver) goal(cells []domain) ast.Goal {
+	goal := make(ast.Goal, s.height)
+	for r := range goal {
+		goal[r] = make([]ast.Char, s.width)
+		for c := range goal[r] {
+			goal[r][c] = s.palette.char(cells[r*s.width+c])
+		}
+	}
+
+	return goal
+}
+
+// colors returns bits of the domain.
+func colors(d domain) []domain {
+	result := make([]domain, 0, bits.OnesCount64(uint64(d)))
+	for d != 0 {
+		color := d & -d
+		result = append(result, color)
+		d &^= color
+	}
+
+	return result
+}
@@ -0,0 +1,268 @@
+package solver_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/solver"
+	"github.com/alexeyco/hanjie/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+// puzzle returns the puzzle of the clue written in the compact notation.
+func puzzle(t *testing.T, columns, rows []string) ast.Puzzle {
+	t.Helper()
+
+	p := ast.Puzzle{
+		Background: ast.Char('.'),
+		Colors:     ast.MonochromeColors(),
+	}
+
+	for _, lines := range [...]struct {
+		source []string
+		target *[]ast.Line
+	}{
+		{source: columns, target: &p.Clue.Columns},
+		{source: rows, target: &p.Clue.Rows},
+	} {
+		for _, s := range lines.source {
+			line, err := ast.ParseLine(s)
+			assert.NoError(t, err)
+
+			*lines.target = append(*lines.target, line)
+		}
+	}
+
+	return p
+}
+
+// goal returns the goal of the rows, "?" is unknown.
+func goal(rows ...string) ast.Goal {
+	result := make(ast.Goal, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, cells(row))
+	}
+
+	return result
+}
+
+func TestSolve(t *testing.T) {
+	t.Parallel()
+
+	testData := [...]struct {
+		name          string
+		columns       []string
+		rows          []string
+		status        solver.Status
+		goal          ast.Goal
+		stats         solver.Stats
+		contradiction *solver.Line
+	}{
+		{
+			name:    "Lines",
+			columns: []string{"1", "3", "1"},
+			rows:    []string{"1", "3", "1"},
+			status:  solver.Solved,
+			goal:    goal(".x.", "xxx", ".x."),
+			stats:   solver.Stats{Lines: 4},
+		},
+		{
+			name:    "Guesses",
+			columns: []string{"2", "1 1", "2"},
+			rows:    []string{"2", "1 1", "2"},
+			status:  solver.Solved,
+			goal:    goal(".xx", "x.x", "xx."),
+			stats:   solver.Stats{Lines: 3, Guesses: 1},
+		},
+		{
+			name:          "ContradictionTooLong",
+			columns:       []string{"1", "1"},
+			rows:          []string{"3"},
+			status:        solver.Contradiction,
+			contradiction: &solver.Line{Index: 0},
+		},
+		{
+			name:          "ContradictionCounts",
+			columns:       []string{"1", ""},
+			rows:          []string{"1", "1"},
+			status:        solver.Contradiction,
+			stats:         solver.Stats{Lines: 3},
+			contradiction: &solver.Line{Column: true, Index: 0},
+		},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := solver.Solve(context.Background(), puzzle(t, testDatum.columns, testDatum.rows))
+
+			assert.NoError(t, err)
+			assert.Equal(t, testDatum.status, actual.Status)
+			assert.Equal(t, testDatum.goal, actual.Goal)
+			assert.Equal(t, testDatum.stats, actual.Stats)
+			assert.Equal(t, testDatum.contradiction, actual.Contradiction)
+		})
+	}
+
+	t.Run("ContradictionBackgroundItem", func(t *testing.T) {
+		t.Parallel()
+
+		p := puzzle(t, []string{"1"}, []string{"1"})
+		p.Clue.Columns[0][0].Color = ast.Char('.')
+
+		actual, err := solver.Solve(context.Background(), p)
+
+		assert.NoError(t, err)
+		assert.Equal(t, solver.Contradiction, actual.Status)
+		assert.Equal(t, "clue.columns[0]", actual.Contradiction.String())
+	})
+
+	t.Run("PartialCanceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		actual, err := solver.Solve(ctx, puzzle(t, []string{"1", "3", "1"}, []string{"1", "3", "1"}))
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, solver.Partial, actual.Status)
+		assert.Equal(t, goal("???", "???", "???"), actual.Goal)
+	})
+
+	t.Run("MultiColored", func(t *testing.T) {
+		t.Parallel()
+
+		colors := []ast.Char{'.', 'x', 'y', 'z'}
+		random := rand.New(rand.NewSource(1))
+
+		for n := 0; n < 50; n++ {
+			g := make(ast.Goal, 1+random.Intn(8))
+			width := 1 + random.Intn(8)
+
+			for r := range g {
+				g[r] = make([]ast.Char, width)
+				for c := range g[r] {
+					g[r][c] = colors[random.Intn(len(colors))]
+				}
+			}
+
+			p := ast.Puzzle{Background: ast.Char('.'), Clue: tools.GoalToClue(g, ast.Char('.'))}
+
+			actual, err := solver.Solve(context.Background(), p)
+
+			assert.NoError(t, err)
+			assert.Equal(t, solver.Solved, actual.Status, "goal %v", g)
+			assert.Equal(t, p.Clue, tools.GoalToClue(actual.Goal, ast.Char('.')), "goal %v", g)
+		}
+	})
+}
+
+func TestSolutions(t *testing.T) {
+	t.Parallel()
+
+	testData := [...]struct {
+		name     string
+		columns  []string
+		rows     []string
+		limit    int
+		expected []ast.Goal
+	}{
+		{
+			name:     "Unique",
+			columns:  []string{"1", "3", "1"},
+			rows:     []string{"1", "3", "1"},
+			limit:    2,
+			expected: []ast.Goal{goal(".x.", "xxx", ".x.")},
+		},
+		{
+			name:     "Multiple",
+			columns:  []string{"2", "1 1", "2"},
+			rows:     []string{"2", "1 1", "2"},
+			limit:    3,
+			expected: []ast.Goal{goal(".xx", "x.x", "xx."), goal("xx.", "x.x", ".xx")},
+		},
+		{
+			name:     "Limit",
+			columns:  []string{"1", "1"},
+			rows:     []string{"1", "1"},
+			limit:    1,
+			expected: []ast.Goal{goal(".x", "x.")},
+		},
+		{
+			name:    "ZeroLimit",
+			columns: []string{"1", "3", "1"},
+			rows:    []string{"1", "3", "1"},
+		},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := solver.Solutions(context.Background(), puzzle(t, testDatum.columns, testDatum.rows), testDatum.limit)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testDatum.expected, actual)
+		})
+	}
+
+	t.Run("ErrorContradiction", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := solver.Solutions(context.Background(), puzzle(t, []string{"1", "1"}, []string{"3"}), 2)
+
+		var contradictionError *solver.ContradictionError
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrContradiction)
+		assert.ErrorAs(t, err, &contradictionError)
+		assert.Equal(t, solver.Line{Index: 0}, contradictionError.Line)
+		assert.EqualError(t, err, "contradiction in clue.rows[0]")
+	})
+
+	t.Run("ErrorCanceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		actual, err := solver.Solutions(ctx, puzzle(t, []string{"1", "1"}, []string{"1", "1"}), 2)
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestStatus_String(t *testing.T) {
+	t.Parallel()
+
+	testData := [...]struct {
+		name     string
+		status   solver.Status
+		expected string
+	}{
+		{name: "Partial", status: solver.Partial, expected: "partial"},
+		{name: "Solved", status: solver.Solved, expected: "solved"},
+		{name: "Contradiction", status: solver.Contradiction, expected: "contradiction"},
+		{name: "Unknown", status: solver.Status(42), expected: "Status(42)"},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testDatum.expected, testDatum.status.String())
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package hanjie
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+)
+
+// Decoder reads puzzles one at a time from a stream of "---"-separated YAML documents.
+// Every document holds either a single puzzle or a set of puzzles of any supported version.
+type Decoder struct {
+	decoder *yaml.Decoder
+	limited *limitedReader
+	options Options
+	pending []*yaml.Node
+	index   int
+	err     error
+}
+
+// NewDecoder returns a new decoder that reads from r.
+func NewDecoder(r io.Reader, options ...Option) *Decoder {
+	o := newOptions()
+	for _, opt := range options {
+		opt(&o)
+	}
+
+	limited := limitReader(r, o)
+
+	return &Decoder{
+		decoder: yaml.NewDecoder(limited),
+		limited: limited,
+		options: o,
+	}
+}
+
+// Decode returns the next puzzle of the stream, or io.EOF at the end of the stream.
+// The puzzle is validated unless SkipValidation is set. An invalid puzzle is reported with errors.ValidationError
+// and decoding may go on with the next one.
+func (d *Decoder) Decode() (*ast.Puzzle, error) {
+	for len(d.pending) == 0 {
+		if err := d.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	node := d.pending[0]
+	d.pending = d.pending[1:]
+
+	index := d.index
+	d.index++
+
+	if maxPuzzles := d.options.Limits.MaxPuzzles; maxPuzzles > 0 && index >= maxPuzzles {
+		return nil, fmt.Errorf("%w: more than %d puzzles", errors.ErrLimitExceeded, maxPuzzles)
+	}
+
+	if d.options.Strict {
+		if err := checkYAMLFields(d.options, node, puzzleType); err != nil {
+			return nil, err
+		}
+	}
+
+	var puzzle ast.Puzzle
+	if err := node.Decode(&puzzle); err != nil {
+		return nil, syntaxError(d.options, err)
+	}
+
+	puzzleSet := ast.PuzzleSet{puzzle}
+
+	err := checkLimits(d.options, puzzleSet)
+	if err == nil {
+		_, err = validate(d.options, puzzleSet)
+	}
+
+	if err == nil {
+		return &puzzleSet[0], nil
+	}
+
+	locate(d.options, func(int) *yaml.Node {
+		return node
+	}, err)
+	reindex(err, index)
+
+	return nil, err
+}
+
+// next reads puzzle nodes of the next document.
+func (d *Decoder) next() error {
+	if d.err != nil {
+		return d.err
+	}
+
+	// yaml.Decoder can't go on after an error, so the error is returned by the following calls too.
+	var document yaml.Node
+	if err := d.decoder.Decode(&document); err != nil {
+		d.err = syntaxError(d.options, d.limited.cause(err))
+
+		return d.err
+	}
+
+	node := resolve(&document)
+
+	switch {
+	case node.Kind == yaml.SequenceNode:
+		d.pending = append(d.pending, node.Content...)
+	case node.Kind == yaml.MappingNode && child(node, versionKey) != nil:
+		if d.options.Strict {
+			if err := checkYAMLFields(d.options, node, reflect.TypeOf(strictEnvelope{})); err != nil {
+				return err
+			}
+		}
+
+		puzzles, err := upgrade(d.options, node)
+		if err != nil {
+			return err
+		}
+
+		d.pending = append(d.pending, puzzles.Content...)
+	case node.Kind == yaml.MappingNode:
+		d.pending = append(d.pending, node)
+	case node.Kind == yaml.ScalarNode && node.ShortTag() == "!!null":
+	default:
+		return &errors.SyntaxError{
+			Position: errors.Position{File: d.options.Filename, Line: node.Line, Column: node.Column},
+			Err:      fmt.Errorf("%w: document should be a puzzle or a list of puzzles", errors.ErrSyntax),
+		}
+	}
+
+	return nil
+}
+
+// Encoder writes puzzles one at a time as a stream of "---"-separated YAML documents.
+type Encoder struct {
+	encoder *yaml.Encoder
+	options Options
+	index   int
+}
+
+// NewEncoder returns a new encoder that writes to w.
+func NewEncoder(w io.Writer, options ...Option) *Encoder {
+	o := newOptions()
+	for _, opt := range options {
+		opt(&o)
+	}
+
+	return &Encoder{
+		encoder: yaml.NewEncoder(w),
+		options: o,
+	}
+}
+
+// Encode writes the puzzle as a separate document.
+// The puzzle is validated unless SkipValidation is set, an invalid puzzle is not written.
+// Rules fixing the puzzle, e.g. validator.FillGoal, change the written copy only.
+// The puzzle is written in the version set by WithVersion the same way Write does.
+func (e *Encoder) Encode(puzzle *ast.Puzzle) error {
+	index := e.index
+	e.index++
+
+	valid, err := validate(e.options, writable(e.options, ast.PuzzleSet{*puzzle}))
+	if err != nil {
+		reindex(err, index)
+
+		return err
+	}
+
+	var puzzles yaml.Node
+	if err = puzzles.Encode(valid); err != nil {
+		return err
+	}
+
+	document, err := downgrade(&puzzles, e.options.Version)
+	if err != nil {
+		return err
+	}
+
+	if document.Kind == yaml.MappingNode {
+		document = child(document, puzzlesKey)
+	}
+
+	return e.encoder.Encode(document.Content[0])
+}
+
+// Close flushes the stream. It doesn't close the underlying writer.
+func (e *Encoder) Close() error {
+	return e.encoder.Close()
+}
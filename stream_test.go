@@ -0,0 +1,222 @@
+package hanjie_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/alexeyco/hanjie"
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/tools"
+	"github.com/alexeyco/hanjie/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+var expectedStreamString = `id: id
+source: https://foo.bar
+author:
+    name: John Doe
+    id: johnDoe
+copyright: '&copy; John Doe'
+title: Puzzle
+description: Very beautiful puzzle
+background: .
+colors:
+    .: '#ffffff'
+    x: '#000000'
+clue:
+    columns: ["2", "1 1", "2"]
+    rows: ["2", "1 1", "2"]
+goal:
+    - xx.
+    - x.x
+    - .xx
+`
+
+func TestDecoder_Decode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		input := expectedStreamString + "---\n" + expectedString + "---\n" + expectedStreamString
+
+		decoder := hanjie.NewDecoder(strings.NewReader(input))
+
+		for i := 0; i < 3; i++ {
+			actual, err := decoder.Decode()
+
+			assert.NoError(t, err)
+			assert.Equal(t, &(*expectedPuzzleSet)[0], actual)
+		}
+
+		actual, err := decoder.Decode()
+
+		assert.Nil(t, actual)
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("ErrorCauseInvalidPuzzle", func(t *testing.T) {
+		t.Parallel()
+
+		input := legacyString + untitledString + "---\n" + expectedStreamString
+
+		decoder := hanjie.NewDecoder(strings.NewReader(input))
+
+		actual, err := decoder.Decode()
+
+		assert.NoError(t, err)
+		assert.NotNil(t, actual)
+
+		actual, err = decoder.Decode()
+
+		var puzzleError *errors.PuzzleError
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrEmptyTitle)
+		assert.ErrorAs(t, err, &puzzleError)
+		assert.Equal(t, 1, puzzleError.Index)
+		assert.Equal(t, 17, puzzleError.Position.Line)
+
+		actual, err = decoder.Decode()
+
+		assert.NoError(t, err)
+		assert.NotNil(t, actual)
+	})
+
+	t.Run("OkVersionedDocument", func(t *testing.T) {
+		t.Parallel()
+
+		decoder := hanjie.NewDecoder(strings.NewReader(expectedString + "---\n" + legacyString))
+
+		for i := 0; i < 2; i++ {
+			actual, err := decoder.Decode()
+
+			assert.NoError(t, err)
+			assert.Equal(t, &(*expectedPuzzleSet)[0], actual)
+		}
+
+		actual, err := decoder.Decode()
+
+		assert.Nil(t, actual)
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("ErrorCauseUnsupportedVersion", func(t *testing.T) {
+		t.Parallel()
+
+		input := strings.Replace(expectedString, "version: 2", "version: 3", 1)
+
+		decoder := hanjie.NewDecoder(strings.NewReader(input))
+
+		actual, err := decoder.Decode()
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrUnsupportedVersion)
+	})
+
+	t.Run("ErrorCauseScalarDocument", func(t *testing.T) {
+		t.Parallel()
+
+		decoder := hanjie.NewDecoder(strings.NewReader("foo\n"))
+
+		actual, err := decoder.Decode()
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrSyntax)
+	})
+}
+
+func TestEncoder_Encode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		encoder := hanjie.NewEncoder(&buf)
+		puzzle := (*expectedPuzzleSet)[0]
+
+		assert.NoError(t, encoder.Encode(&puzzle))
+		assert.NoError(t, encoder.Encode(&puzzle))
+		assert.NoError(t, encoder.Close())
+		assert.Equal(t, expectedStreamString+"---\n"+expectedStreamString, buf.String())
+	})
+
+	t.Run("OkVersion1", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		encoder := hanjie.NewEncoder(&buf, hanjie.WithVersion(1))
+		puzzle := (*expectedPuzzleSet)[0]
+
+		assert.NoError(t, encoder.Encode(&puzzle))
+		assert.NoError(t, encoder.Close())
+
+		actual := buf.String()
+
+		assert.Contains(t, actual, "    rows: [[{color: x, count: 2}], [{color: x, count: 1}, {color: x, count: 1}], [{color: x, count: 2}]]\n")
+		assert.Contains(t, actual, "goal: [[x, x, .], [x, ., x], [., x, x]]\n")
+
+		decoded, err := hanjie.NewDecoder(&buf).Decode()
+
+		assert.NoError(t, err)
+		assert.Equal(t, &puzzle, decoded)
+	})
+
+	t.Run("OkFillGoal", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		encoder := hanjie.NewEncoder(&buf, hanjie.WithValidator(validator.New(validator.FillGoal)))
+		puzzle := (*expectedPuzzleSet)[0]
+		puzzle.Clue = tools.GoalToClue(uniqueGoal, ast.Char('.'))
+		puzzle.Goal = nil
+
+		assert.NoError(t, encoder.Encode(&puzzle))
+		assert.NoError(t, encoder.Close())
+		assert.Contains(t, buf.String(), "goal:\n    - xx.\n    - x..\n    - '...'\n")
+		assert.Nil(t, puzzle.Goal)
+	})
+
+	t.Run("ErrorCauseUnsupportedVersion", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		encoder := hanjie.NewEncoder(&buf, hanjie.WithVersion(3))
+		puzzle := (*expectedPuzzleSet)[0]
+
+		assert.ErrorIs(t, encoder.Encode(&puzzle), errors.ErrUnsupportedVersion)
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("ErrorCauseInvalidPuzzle", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		encoder := hanjie.NewEncoder(&buf)
+		puzzle := (*expectedPuzzleSet)[0]
+		untitled := puzzle
+		untitled.Title = ""
+
+		assert.NoError(t, encoder.Encode(&puzzle))
+
+		err := encoder.Encode(&untitled)
+
+		var puzzleError *errors.PuzzleError
+
+		assert.ErrorIs(t, err, errors.ErrEmptyTitle)
+		assert.ErrorAs(t, err, &puzzleError)
+		assert.Equal(t, 1, puzzleError.Index)
+
+		assert.NoError(t, encoder.Close())
+		assert.Equal(t, expectedStreamString, buf.String())
+	})
+}
@@ -0,0 +1,265 @@
+package hanjie
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+)
+
+var puzzleType = reflect.TypeOf(ast.Puzzle{})
+
+// strictDocument describes keys of the document of version 2 and later.
+type strictDocument struct {
+	Version        int `yaml:"version" json:"version"`
+	ast.Collection `yaml:",inline"`
+}
+
+// strictEnvelope describes keys of the document leaving puzzles unchecked,
+// the outer field takes precedence over the embedded one.
+type strictEnvelope struct {
+	strictDocument `yaml:",inline"`
+	Puzzles        interface{} `yaml:"puzzles"`
+}
+
+// documentType returns the type the upgraded document node is decoded into.
+func documentType(node *yaml.Node) reflect.Type {
+	if resolve(node).Kind == yaml.MappingNode {
+		return reflect.TypeOf(strictDocument{})
+	}
+
+	return reflect.TypeOf(ast.PuzzleSet{})
+}
+
+// checkYAMLFields reports unknown and duplicate keys of the node decoded into type t.
+func checkYAMLFields(o Options, node *yaml.Node, t reflect.Type) error {
+	var validationError errors.ValidationError
+
+	walkYAML(node, t, map[visit]bool{}, func(key *yaml.Node, err error) {
+		validationError.Append(&errors.SyntaxError{
+			Position: errors.Position{File: o.Filename, Line: key.Line, Column: key.Column},
+			Err:      fmt.Errorf(`%w "%s"`, err, key.Value),
+		})
+	})
+
+	if len(validationError) == 0 {
+		return nil
+	}
+
+	return validationError
+}
+
+// visit is the node walked as the type.
+type visit struct {
+	node *yaml.Node
+	t    reflect.Type
+}
+
+// walkYAML reports keys of the node unknown to type t. Nodes are walked once per type, so aliases
+// repeating the anchored node, e.g. in billion laughs documents, don't multiply the work.
+func walkYAML(node *yaml.Node, t reflect.Type, visited map[visit]bool, report func(key *yaml.Node, err error)) {
+	node = resolve(node)
+	t = indirect(t)
+
+	if visited[visit{node: node, t: t}] {
+		return
+	}
+
+	visited[visit{node: node, t: t}] = true
+
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, n := range node.Content {
+			walkYAML(n, elem(t), visited, report)
+		}
+	case yaml.MappingNode:
+		fields := knownFields(t, "yaml")
+		seen := map[string]bool{}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+
+			value, err := fieldType(t, fields, key.Value, seen)
+			if err != nil {
+				report(key, err)
+			}
+
+			walkYAML(node.Content[i+1], value, visited, report)
+		}
+	}
+}
+
+// checkJSONFields reports unknown and duplicate keys of the JSON data decoded into type t.
+func checkJSONFields(o Options, data []byte, t reflect.Type) error {
+	c := jsonChecker{
+		options: o,
+		data:    data,
+		decoder: json.NewDecoder(bytes.NewReader(data)),
+	}
+
+	if err := c.walk(t); err != nil {
+		return syntaxError(o, jsonSyntaxError(data, err))
+	}
+
+	if len(c.validationError) == 0 {
+		return nil
+	}
+
+	return c.validationError
+}
+
+type jsonChecker struct {
+	options         Options
+	data            []byte
+	decoder         *json.Decoder
+	validationError errors.ValidationError
+}
+
+func (c *jsonChecker) walk(t reflect.Type) error {
+	token, err := c.decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return nil
+	}
+
+	t = indirect(t)
+
+	switch delim {
+	case '[':
+		for c.decoder.More() {
+			if err := c.walk(elem(t)); err != nil {
+				return err
+			}
+		}
+	case '{':
+		fields := knownFields(t, "json")
+		seen := map[string]bool{}
+
+		for c.decoder.More() {
+			offset := c.keyOffset()
+
+			token, err := c.decoder.Token()
+			if err != nil {
+				return err
+			}
+
+			key, _ := token.(string)
+
+			value, err := fieldType(t, fields, key, seen)
+			if err != nil {
+				position := jsonPosition(c.data, offset)
+				position.File = c.options.Filename
+
+				c.validationError.Append(&errors.SyntaxError{
+					Position: position,
+					Err:      fmt.Errorf(`%w "%s"`, err, key),
+				})
+			}
+
+			if err := c.walk(value); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = c.decoder.Token()
+
+	return err
+}
+
+// keyOffset returns offset of the next object key.
+func (c *jsonChecker) keyOffset() int64 {
+	offset := c.decoder.InputOffset()
+	for offset < int64(len(c.data)) && strings.IndexByte(" \t\r\n,", c.data[offset]) >= 0 {
+		offset++
+	}
+
+	return offset
+}
+
+// fieldType returns type of the mapping value by the key, unknown and duplicate keys are reported with errors.
+func fieldType(t reflect.Type, fields map[string]reflect.Type, key string, seen map[string]bool) (reflect.Type, error) {
+	if seen[key] {
+		return nil, errors.ErrDuplicateKey
+	}
+
+	seen[key] = true
+
+	switch {
+	case t == nil:
+		return nil, nil
+	case t.Kind() == reflect.Map:
+		return t.Elem(), nil
+	case t.Kind() != reflect.Struct:
+		return nil, nil
+	}
+
+	if value, ok := fields[key]; ok {
+		return value, nil
+	}
+
+	if t == puzzleType && ast.IsExtension(key) {
+		return nil, nil
+	}
+
+	return nil, errors.ErrUnknownField
+}
+
+// knownFields returns types of struct fields by their names in the tag.
+func knownFields(t reflect.Type, tag string) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fields
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for embedded, value := range knownFields(field.Type, tag) {
+				fields[embedded] = value
+			}
+
+			continue
+		}
+
+		if field.PkgPath != "" || name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		fields[name] = field.Type
+	}
+
+	return fields
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+func elem(t reflect.Type) reflect.Type {
+	if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		return t.Elem()
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package hanjie_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexeyco/hanjie"
+	"github.com/alexeyco/hanjie/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrict(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		input := strings.Replace(expectedString, "  - id: id\n", "  - id: id\n    x-rating: 5\n", 1)
+
+		actual, err := hanjie.Read(strings.NewReader(input), hanjie.Strict)
+
+		assert.NoError(t, err)
+		assert.Len(t, *actual, 1)
+	})
+
+	t.Run("OkVersion1", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := hanjie.Read(strings.NewReader(legacyString), hanjie.Strict)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPuzzleSet, actual)
+	})
+
+	t.Run("OkJSON", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := hanjie.ReadJSON(strings.NewReader(`{"version": 2, "puzzles": `+expectedJSONString+`}`), hanjie.Strict)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPuzzleSet, actual)
+	})
+
+	testData := [...]struct {
+		name      string
+		input     string
+		options   []hanjie.Option
+		err       error
+		positions []errors.Position
+	}{
+		{
+			name: "UnknownFields",
+			input: strings.NewReplacer(
+				"    background: .", "    backround: .",
+				"    colors:", "    colours:",
+				"      name: John Doe", "      nick: John Doe",
+			).Replace(expectedString),
+			err: errors.ErrUnknownField,
+			positions: []errors.Position{
+				{File: "foo.yml", Line: 6, Column: 7},
+				{File: "foo.yml", Line: 11, Column: 5},
+				{File: "foo.yml", Line: 12, Column: 5},
+			},
+		},
+		{
+			name:      "UnknownDocumentField",
+			input:     "comment: foo\n" + expectedString,
+			err:       errors.ErrUnknownField,
+			positions: []errors.Position{{File: "foo.yml", Line: 1, Column: 1}},
+		},
+		{
+			name: "DuplicateColor",
+			input: `[{"title": "Puzzle", "background": ".", "colors": {".": "#fff", "x": "#000", "x": "#f00"},
+"clue": {"columns": ["1"], "rows": ["1"]}}]`,
+			options:   []hanjie.Option{hanjie.WithFormat(hanjie.FormatJSON)},
+			err:       errors.ErrDuplicateKey,
+			positions: []errors.Position{{File: "foo.yml", Line: 1, Column: 78}},
+		},
+		{
+			name: "UnknownJSONField",
+			input: `[{"title": "Puzzle", "background": ".", "colors": {".": "#fff", "x": "#000"},
+"clue": {"columns": ["1"], "rows": ["1"], "cols": []}}]`,
+			options:   []hanjie.Option{hanjie.WithFormat(hanjie.FormatJSON)},
+			err:       errors.ErrUnknownField,
+			positions: []errors.Position{{File: "foo.yml", Line: 2, Column: 43}},
+		},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			options := append([]hanjie.Option{hanjie.Strict, hanjie.WithFilename("foo.yml")}, testDatum.options...)
+
+			actual, err := hanjie.Read(strings.NewReader(testDatum.input), options...)
+
+			var validationError errors.ValidationError
+
+			assert.Nil(t, actual)
+			assert.ErrorIs(t, err, testDatum.err)
+			assert.ErrorIs(t, err, errors.ErrSyntax)
+			assert.ErrorAs(t, err, &validationError)
+
+			positions := make([]errors.Position, 0, len(validationError))
+			for _, e := range validationError {
+				var syntaxError *errors.SyntaxError
+				if assert.ErrorAs(t, e, &syntaxError) {
+					positions = append(positions, syntaxError.Position)
+				}
+			}
+
+			assert.Equal(t, testDatum.positions, positions)
+		})
+	}
+
+	t.Run("Decoder", func(t *testing.T) {
+		t.Parallel()
+
+		input := strings.Replace(expectedStreamString, "title: Puzzle", "titel: Puzzle", 1) + "---\n" + expectedStreamString
+
+		decoder := hanjie.NewDecoder(strings.NewReader(input), hanjie.Strict)
+
+		actual, err := decoder.Decode()
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, errors.ErrUnknownField)
+
+		actual, err = decoder.Decode()
+
+		assert.NoError(t, err)
+		assert.NotNil(t, actual)
+	})
+
+	t.Run("ErrorAliasBomb", func(t *testing.T) {
+		t.Parallel()
+
+		input := strings.Replace(expectedString, "  - id: id\n", "  - id: id\n"+aliasBomb(9), 1)
+
+		for name, read := range map[string]func() error{
+			"Read": func() error {
+				_, err := hanjie.Read(strings.NewReader(input), hanjie.Strict, hanjie.WithLimits(10, 10, 10, 10, 1<<20))
+
+				return err
+			},
+			"Decoder": func() error {
+				_, err := hanjie.NewDecoder(strings.NewReader(input), hanjie.Strict).Decode()
+
+				return err
+			},
+		} {
+			done := make(chan error, 1)
+			go func() {
+				done <- read()
+			}()
+
+			select {
+			case err := <-done:
+				assert.Error(t, err, name)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("%s: alias bomb isn't rejected in time", name)
+			}
+		}
+	})
+}
+
+// aliasBomb returns puzzle extensions where every level holds 9 aliases of the previous one.
+func aliasBomb(levels int) string {
+	var b strings.Builder
+
+	b.WriteString(`    x-l0: &l0 ["lol", "lol", "lol", "lol", "lol", "lol", "lol", "lol", "lol"]` + "\n")
+
+	for i := 1; i < levels; i++ {
+		alias := fmt.Sprintf("*l%d", i-1)
+		fmt.Fprintf(&b, "    x-l%d: &l%d [%s]\n", i, i, strings.TrimSuffix(strings.Repeat(alias+", ", 9), ", "))
+	}
+
+	return b.String()
+}
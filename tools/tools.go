@@ -1,7 +1,12 @@
 // Package tools contains hanjie tools.
 package tools
 
-import "github.com/alexeyco/hanjie/ast"
+import (
+	"fmt"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+)
 
 // GoalToClue returns clue by goal.
 func GoalToClue(goal ast.Goal, background ast.Char) ast.Clue {
@@ -76,3 +81,93 @@ func TransposeGoal(goal ast.Goal) ast.Goal {
 
 	return transposed
 }
+
+// Foreground returns the only non-background color of the monochrome puzzle.
+// If the puzzle has no foreground color, ast.ForegroundChar is returned.
+func Foreground(puzzle ast.Puzzle) (ast.Char, error) {
+	foreground := ast.ForegroundChar
+	found := false
+
+	for ch := range puzzle.Colors {
+		if ch == puzzle.Background {
+			continue
+		}
+
+		if found {
+			return 0, fmt.Errorf("%w: puzzle has %d colors", errors.ErrMonochromeOnly, len(puzzle.Colors))
+		}
+
+		foreground = ch
+		found = true
+	}
+
+	return foreground, nil
+}
+
+// Normalize returns a copy of the puzzle in the normal form: clue items with zero counts are dropped,
+// missing lines and colors are empty rather than nil, an author without name and ID is dropped,
+// empty extensions are nil.
+func Normalize(puzzle ast.Puzzle) ast.Puzzle {
+	normalized := puzzle
+
+	if puzzle.Author != nil {
+		normalized.Author = nil
+
+		if *puzzle.Author != (ast.Author{}) {
+			author := *puzzle.Author
+			normalized.Author = &author
+		}
+	}
+
+	normalized.Colors = ast.Colors{}
+	for ch, color := range puzzle.Colors {
+		normalized.Colors[ch] = color
+	}
+
+	normalized.Clue = ast.Clue{
+		Columns: normalizeLines(puzzle.Clue.Columns),
+		Rows:    normalizeLines(puzzle.Clue.Rows),
+	}
+
+	normalized.Extensions = nil
+	if len(puzzle.Extensions) > 0 {
+		normalized.Extensions = make(map[string]interface{}, len(puzzle.Extensions))
+		for key, value := range puzzle.Extensions {
+			normalized.Extensions[key] = value
+		}
+	}
+
+	if puzzle.Goal != nil {
+		goal := make(ast.Goal, 0, len(*puzzle.Goal))
+		for _, row := range *puzzle.Goal {
+			goal = append(goal, append([]ast.Char{}, row...))
+		}
+
+		normalized.Goal = &goal
+	}
+
+	if puzzle.Difficulty != nil {
+		difficulty := *puzzle.Difficulty
+		normalized.Difficulty = &difficulty
+	}
+
+	return normalized
+}
+
+func normalizeLines(lines []ast.Line) []ast.Line {
+	normalized := make([]ast.Line, 0, len(lines))
+
+	for _, line := range lines {
+		items := ast.Line{}
+
+		for _, item := range line {
+			if item.Count != 0 {
+				items = append(items, item)
+			}
+		}
+
+		normalized = append(normalized, items)
+	}
+
+	return normalized
+}
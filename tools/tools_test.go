@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
 	"github.com/alexeyco/hanjie/tools"
 	"github.com/stretchr/testify/assert"
 )
@@ -149,3 +150,98 @@ func TestTransposeGoal(t *testing.T) {
 		})
 	}
 }
+
+func TestForeground(t *testing.T) {
+	t.Parallel()
+
+	testData := [...]struct {
+		name     string
+		colors   ast.Colors
+		expected ast.Char
+		err      error
+	}{
+		{
+			name:     "Monochrome",
+			colors:   ast.Colors{ast.Char('.'): ast.Color{}, ast.Char('#'): ast.Color{R: 255}},
+			expected: ast.Char('#'),
+		},
+		{
+			name:     "BackgroundOnly",
+			colors:   ast.Colors{ast.Char('.'): ast.Color{}},
+			expected: ast.ForegroundChar,
+		},
+		{
+			name: "MultiColored",
+			colors: ast.Colors{
+				ast.Char('.'): ast.Color{},
+				ast.Char('x'): ast.Color{R: 255},
+				ast.Char('y'): ast.Color{G: 255},
+			},
+			err: errors.ErrMonochromeOnly,
+		},
+	}
+
+	for _, testDatum := range testData {
+		testDatum := testDatum
+
+		t.Run(testDatum.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := tools.Foreground(ast.Puzzle{
+				Background: ast.Char('.'),
+				Colors:     testDatum.colors,
+			})
+
+			assert.Equal(t, testDatum.expected, actual)
+			if testDatum.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, testDatum.err)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	x := ast.Char('x')
+	goal := ast.Goal{{x}}
+
+	puzzle := ast.Puzzle{
+		Author:     &ast.Author{},
+		Title:      "Puzzle",
+		Background: ast.Char('.'),
+		Clue: ast.Clue{
+			Columns: []ast.Line{{{Color: x, Count: 0}, {Color: x, Count: 1}}},
+			Rows:    []ast.Line{nil},
+		},
+		Goal:       &goal,
+		Difficulty: &ast.Difficulty{Score: 1, Label: "easy", Lines: 1},
+		Extensions: map[string]interface{}{},
+	}
+
+	expected := ast.Puzzle{
+		Title:      "Puzzle",
+		Background: ast.Char('.'),
+		Colors:     ast.Colors{},
+		Clue: ast.Clue{
+			Columns: []ast.Line{{{Color: x, Count: 1}}},
+			Rows:    []ast.Line{{}},
+		},
+		Goal:       &ast.Goal{{x}},
+		Difficulty: &ast.Difficulty{Score: 1, Label: "easy", Lines: 1},
+	}
+
+	actual := tools.Normalize(puzzle)
+
+	assert.Equal(t, expected, actual)
+
+	(*actual.Goal)[0][0] = ast.Char('.')
+
+	assert.Equal(t, x, goal[0][0])
+
+	actual.Difficulty.Score = 2
+
+	assert.Equal(t, 1, puzzle.Difficulty.Score)
+}
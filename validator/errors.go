@@ -0,0 +1,31 @@
+package validator
+
+import (
+	"strings"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+)
+
+// MultipleSolutionsError reports that the clue has more than one solution, see UniqueSolution.
+// It always matches errors.ErrMultipleSolutions.
+type MultipleSolutionsError struct {
+	// Solution is a solution other than the goal of the puzzle, or the second one found if the puzzle has no goal.
+	// Cells that differ from the goal are ambiguous.
+	Solution ast.Goal
+}
+
+// Error returns multiple solutions error message with the example solution.
+func (e *MultipleSolutionsError) Error() string {
+	rows := make([]string, 0, len(e.Solution))
+	for _, row := range e.Solution {
+		rows = append(rows, string(row))
+	}
+
+	return errors.ErrMultipleSolutions.Error() + `, e.g. ["` + strings.Join(rows, `", "`) + `"]`
+}
+
+// Is reports whether target is errors.ErrMultipleSolutions.
+func (e *MultipleSolutionsError) Is(target error) bool {
+	return target == errors.ErrMultipleSolutions
+}
@@ -1,57 +1,70 @@
 package validator
 
 import (
+	goerrors "errors"
 	"fmt"
-	"reflect"
 	"strings"
 
 	"github.com/alexeyco/hanjie/ast"
 	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/solver"
 	"github.com/alexeyco/hanjie/tools"
 )
 
-type rule func(puzzle ast.Puzzle) (err error, stop bool)
+type rule struct {
+	name  string
+	check func(puzzle *ast.Puzzle) (err error, stop bool)
+}
+
+func fieldError(field string, err error) error {
+	return &errors.PuzzleError{
+		Field: field,
+		Err:   err,
+	}
+}
 
-func authorNameRule(puzzle ast.Puzzle) (err error, stop bool) {
+func authorNameRule(puzzle *ast.Puzzle) (err error, stop bool) {
 	if puzzle.Author != nil && puzzle.Author.Name == "" {
-		err = errors.ErrEmptyAuthorName
+		err = fieldError("author.name", errors.ErrEmptyAuthorName)
 	}
 
 	return
 }
 
-func titleRule(puzzle ast.Puzzle) (err error, stop bool) {
+func titleRule(puzzle *ast.Puzzle) (err error, stop bool) {
 	if puzzle.Title == "" {
-		err = errors.ErrEmptyTitle
+		err = fieldError("title", errors.ErrEmptyTitle)
 	}
 
 	return
 }
 
-func backgroundRule(puzzle ast.Puzzle) (error, bool) {
-	var symbols []string
-	for ch := range puzzle.Colors {
-		if ch == puzzle.Background {
-			return nil, false
-		}
+func backgroundRule(puzzle *ast.Puzzle) (error, bool) {
+	if _, ok := puzzle.Colors[puzzle.Background]; ok {
+		return nil, false
+	}
 
+	symbols := make([]string, 0, len(puzzle.Colors))
+	for _, ch := range puzzle.Colors.Chars() {
 		symbols = append(symbols, string(ch))
 	}
 
-	return fmt.Errorf(`%w, should be one of ["%s"]`,
+	return fieldError("background", fmt.Errorf(`%w, should be one of ["%s"]`,
 		errors.ErrIncorrectBackground,
-		strings.Join(symbols, `", "`)), true
+		strings.Join(symbols, `", "`))), true
 }
 
-func uniqueColorRule(puzzle ast.Puzzle) (err error, stop bool) {
+func uniqueColorRule(puzzle *ast.Puzzle) (err error, stop bool) {
 	used := map[string]bool{}
-	for _, color := range puzzle.Colors {
+	for _, ch := range puzzle.Colors.Chars() {
+		color := puzzle.Colors[ch]
+
 		id := fmt.Sprintf("%d-%d-%d", color.R, color.G, color.B)
 		if _, ok := used[id]; ok {
-			return fmt.Errorf(`%w #%02x%02x%02x {R: %d, G: %d, B: %d}`,
+			return fieldError(fmt.Sprintf("colors[%s]", string(ch)), fmt.Errorf(`%w #%02x%02x%02x {R: %d, G: %d, B: %d}`,
 				errors.ErrColorHasAlreadyBeenUsed,
 				color.R, color.G, color.B,
-				color.R, color.G, color.B), false
+				color.R, color.G, color.B)), false
 		}
 
 		used[id] = true
@@ -60,25 +73,25 @@ func uniqueColorRule(puzzle ast.Puzzle) (err error, stop bool) {
 	return
 }
 
-func goalRowsRule(puzzle ast.Puzzle) (err error, stop bool) {
+func goalRowsRule(puzzle *ast.Puzzle) (err error, stop bool) {
 	if puzzle.Goal != nil && len(*puzzle.Goal) == 0 {
-		err = errors.ErrGoalIsIncorrect
+		err = fieldError("goal", errors.ErrGoalIsIncorrect)
 		stop = true
 	}
 
 	return
 }
 
-func goalLinesRule(puzzle ast.Puzzle) (error, bool) {
+func goalLinesRule(puzzle *ast.Puzzle) (error, bool) {
 	if puzzle.Goal == nil {
 		return nil, false
 	}
 
 	var expectedLen int
-	for _, row := range *puzzle.Goal {
+	for i, row := range *puzzle.Goal {
 		rowLen := len(row)
 		if rowLen == 0 {
-			return errors.ErrGoalIsIncorrect, true
+			return fieldError(fmt.Sprintf("goal[%d]", i), errors.ErrGoalIsIncorrect), true
 		}
 
 		if expectedLen == 0 {
@@ -87,22 +100,121 @@ func goalLinesRule(puzzle ast.Puzzle) (error, bool) {
 		}
 
 		if expectedLen != rowLen {
-			return errors.ErrGoalIsIncorrect, true
+			return fieldError(fmt.Sprintf("goal[%d]", i), errors.ErrGoalIsIncorrect), true
 		}
 	}
 
 	return nil, false
 }
 
-func goalClueMatchRule(puzzle ast.Puzzle) (err error, stop bool) {
+func goalClueMatchRule(puzzle *ast.Puzzle) (err error, stop bool) {
 	if puzzle.Goal == nil {
 		return
 	}
 
 	clue := tools.GoalToClue(*puzzle.Goal, puzzle.Background)
-	if !reflect.DeepEqual(clue, puzzle.Clue) {
-		return errors.ErrGoalDoesNotMatchTheClue, true
+	if field := linesDiff("clue.columns", clue.Columns, puzzle.Clue.Columns); field != "" {
+		return fieldError(field, errors.ErrGoalDoesNotMatchTheClue), true
+	}
+
+	if field := linesDiff("clue.rows", clue.Rows, puzzle.Clue.Rows); field != "" {
+		return fieldError(field, errors.ErrGoalDoesNotMatchTheClue), true
 	}
 
 	return
 }
+
+func (v *Validator) uniqueSolutionRule(puzzle *ast.Puzzle) (err error, stop bool) {
+	solutions, err := solver.Solutions(v.ctx, *puzzle, 2)
+
+	var contradictionError *solver.ContradictionError
+	if goerrors.As(err, &contradictionError) {
+		return fieldError(contradictionError.Line.String(), errors.ErrUnsolvable), true
+	}
+
+	if err != nil {
+		return fieldError("clue", err), true
+	}
+
+	if len(solutions) < 2 {
+		return
+	}
+
+	// The example is a solution other than the goal if the puzzle has one.
+	solution := solutions[1]
+	if puzzle.Goal != nil && equalGoals(solution, *puzzle.Goal) {
+		solution = solutions[0]
+	}
+
+	return fieldError("clue", &MultipleSolutionsError{Solution: solution}), false
+}
+
+func (v *Validator) solvableRule(fill bool) func(puzzle *ast.Puzzle) (error, bool) {
+	return func(puzzle *ast.Puzzle) (error, bool) {
+		if puzzle.Goal != nil {
+			return nil, false
+		}
+
+		// Filling the goal needs to know if the solution is unique, the check needs any solution.
+		limit := 1
+		if fill {
+			limit = 2
+		}
+
+		solutions, err := solver.Solutions(v.ctx, *puzzle, limit)
+
+		var contradictionError *solver.ContradictionError
+		if goerrors.As(err, &contradictionError) {
+			return fieldError(contradictionError.Line.String(), errors.ErrUnsolvable), true
+		}
+
+		if err != nil {
+			return fieldError("clue", err), true
+		}
+
+		if fill && len(solutions) == 1 {
+			puzzle.Goal = &solutions[0]
+		}
+
+		return nil, false
+	}
+}
+
+func equalGoals(a, b ast.Goal) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for r := range a {
+		if string(a[r]) != string(b[r]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// linesDiff returns path of the first clue field that differs from expected one or empty string.
+func linesDiff(field string, expected, actual []ast.Line) string {
+	if len(expected) != len(actual) {
+		return field
+	}
+
+	for i := range expected {
+		if len(expected[i]) != len(actual[i]) {
+			return fmt.Sprintf("%s[%d]", field, i)
+		}
+
+		for j := range expected[i] {
+			if expected[i][j].Color != actual[i][j].Color {
+				return fmt.Sprintf("%s[%d][%d].color", field, i, j)
+			}
+
+			if expected[i][j].Count != actual[i][j].Count {
+				return fmt.Sprintf("%s[%d][%d].count", field, i, j)
+			}
+		}
+	}
+
+	return ""
+}
@@ -2,6 +2,9 @@
 package validator
 
 import (
+	"context"
+	goerrors "errors"
+
 	"github.com/alexeyco/hanjie/ast"
 	"github.com/alexeyco/hanjie/errors"
 )
@@ -9,16 +12,30 @@ import (
 // Validator of the puzzle.
 type Validator struct {
 	rules []rule
+	ctx   context.Context
 }
 
 // Validate the puzzle.
+// Every reported error is *errors.PuzzleError attributed to the puzzle and the field.
+// Puzzles of the set are changed by the rules fixing them only, see FillGoal.
 func (v *Validator) Validate(puzzleSet ast.PuzzleSet) error {
 	var validationError errors.ValidationError
-	for _, puzzle := range puzzleSet {
+	for i := range puzzleSet {
+		puzzle := &puzzleSet[i]
+
 		for _, r := range v.rules {
-			err, stop := r(puzzle)
+			err, stop := r.check(puzzle)
 			if err != nil {
-				validationError.Append(err)
+				var puzzleError *errors.PuzzleError
+				if !goerrors.As(err, &puzzleError) {
+					puzzleError = &errors.PuzzleError{Err: err}
+				}
+
+				puzzleError.Index = i
+				puzzleError.ID = puzzle.ID
+				puzzleError.Rule = r.name
+
+				validationError.Append(puzzleError)
 			}
 
 			if stop {
@@ -34,17 +51,54 @@ func (v *Validator) Validate(puzzleSet ast.PuzzleSet) error {
 	return validationError
 }
 
-// New returns new validator instance.
-func New() *Validator {
-	return &Validator{
+// Option of the validator.
+type Option func(*Validator)
+
+// WithContext sets the context of the rules running the solver, see UniqueSolution and Solvable.
+// Use it to limit the time of the validation: when ctx is done, the rules report the ctx error.
+func WithContext(ctx context.Context) Option {
+	return func(v *Validator) {
+		v.ctx = ctx
+	}
+}
+
+// UniqueSolution checks that the clue has the only solution, see MultipleSolutionsError.
+// Clues without solution are reported with errors.ErrUnsolvable the same way Solvable does.
+// The check runs the solver, so it may take a while for large puzzles, see WithContext.
+func UniqueSolution(v *Validator) {
+	v.rules = append(v.rules, rule{name: "unique-solution", check: v.uniqueSolutionRule})
+}
+
+// Solvable checks that the clue of puzzles without goal has a solution. The line where the solver
+// finds a contradiction is reported with errors.ErrUnsolvable, see WithContext.
+func Solvable(v *Validator) {
+	v.rules = append(v.rules, rule{name: "solvable", check: v.solvableRule(false)})
+}
+
+// FillGoal checks puzzles without goal the same way Solvable does and sets the goal of the puzzle
+// if the clue has the only solution.
+func FillGoal(v *Validator) {
+	v.rules = append(v.rules, rule{name: "solvable", check: v.solvableRule(true)})
+}
+
+// New returns new validator instance, options enable rules which aren't checked by default.
+func New(options ...Option) *Validator {
+	v := &Validator{
 		rules: []rule{
-			authorNameRule,
-			titleRule,
-			backgroundRule,
-			uniqueColorRule,
-			goalRowsRule,
-			goalLinesRule,
-			goalClueMatchRule,
+			{name: "author-name", check: authorNameRule},
+			{name: "title", check: titleRule},
+			{name: "background", check: backgroundRule},
+			{name: "unique-color", check: uniqueColorRule},
+			{name: "goal-rows", check: goalRowsRule},
+			{name: "goal-lines", check: goalLinesRule},
+			{name: "goal-clue-match", check: goalClueMatchRule},
 		},
+		ctx: context.Background(),
+	}
+
+	for _, opt := range options {
+		opt(v)
 	}
+
+	return v
 }
@@ -1,12 +1,14 @@
 package validator_test
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/alexeyco/hanjie/ast"
 	"github.com/alexeyco/hanjie/errors"
+	"github.com/alexeyco/hanjie/tools"
 	"github.com/alexeyco/hanjie/validator"
 	"github.com/stretchr/testify/assert"
 )
@@ -55,7 +57,7 @@ func TestValidator_Validate(t *testing.T) {
 		puzzleSet[0].Author.Name = ""
 
 		expected := errors.ValidationError{
-			errors.ErrEmptyAuthorName,
+			puzzleError("author.name", "author-name", errors.ErrEmptyAuthorName),
 		}
 
 		actual := v.Validate(puzzleSet)
@@ -71,7 +73,7 @@ func TestValidator_Validate(t *testing.T) {
 		puzzleSet[0].Title = ""
 
 		expected := errors.ValidationError{
-			errors.ErrEmptyTitle,
+			puzzleError("title", "title", errors.ErrEmptyTitle),
 		}
 
 		actual := v.Validate(puzzleSet)
@@ -87,7 +89,8 @@ func TestValidator_Validate(t *testing.T) {
 		puzzleSet[0].Background = ast.Char('-')
 
 		expected := errors.ValidationError{
-			fmt.Errorf(`%w, should be one of ["%s"]`, errors.ErrIncorrectBackground, strings.Join([]string{".", "x"}, `", "`)),
+			puzzleError("background", "background",
+				fmt.Errorf(`%w, should be one of ["%s"]`, errors.ErrIncorrectBackground, strings.Join([]string{".", "x"}, `", "`))),
 		}
 
 		actual := v.Validate(puzzleSet)
@@ -107,7 +110,8 @@ func TestValidator_Validate(t *testing.T) {
 		}
 
 		expected := errors.ValidationError{
-			fmt.Errorf(`%w #%02x%02x%02x {R: %d, G: %d, B: %d}`, errors.ErrColorHasAlreadyBeenUsed, 0, 0, 0, 0, 0, 0),
+			puzzleError("colors[y]", "unique-color",
+				fmt.Errorf(`%w #%02x%02x%02x {R: %d, G: %d, B: %d}`, errors.ErrColorHasAlreadyBeenUsed, 0, 0, 0, 0, 0, 0)),
 		}
 
 		actual := v.Validate(puzzleSet)
@@ -123,7 +127,7 @@ func TestValidator_Validate(t *testing.T) {
 		puzzleSet[0].Goal = &ast.Goal{}
 
 		expected := errors.ValidationError{
-			errors.ErrGoalIsIncorrect,
+			puzzleError("goal", "goal-rows", errors.ErrGoalIsIncorrect),
 		}
 
 		actual := v.Validate(puzzleSet)
@@ -141,7 +145,7 @@ func TestValidator_Validate(t *testing.T) {
 		}
 
 		expected := errors.ValidationError{
-			errors.ErrGoalIsIncorrect,
+			puzzleError("goal[0]", "goal-lines", errors.ErrGoalIsIncorrect),
 		}
 
 		actual := v.Validate(puzzleSet)
@@ -161,7 +165,7 @@ func TestValidator_Validate(t *testing.T) {
 		}
 
 		expected := errors.ValidationError{
-			errors.ErrGoalIsIncorrect,
+			puzzleError("goal[1]", "goal-lines", errors.ErrGoalIsIncorrect),
 		}
 
 		actual := v.Validate(puzzleSet)
@@ -181,7 +185,7 @@ func TestValidator_Validate(t *testing.T) {
 		}
 
 		expected := errors.ValidationError{
-			errors.ErrGoalDoesNotMatchTheClue,
+			puzzleError("clue.columns[0][0].count", "goal-clue-match", errors.ErrGoalDoesNotMatchTheClue),
 		}
 
 		actual := v.Validate(puzzleSet)
@@ -189,6 +193,269 @@ func TestValidator_Validate(t *testing.T) {
 		assert.Error(t, actual)
 		assert.Equal(t, expected, actual)
 	})
+
+	t.Run("ErrorCauseClueItemDoesNotMatchTheGoal", func(t *testing.T) {
+		t.Parallel()
+
+		puzzleSet := newPuzzleSet()
+		puzzleSet[0].Clue.Rows[1][1].Color = ast.Char('.')
+
+		expected := errors.ValidationError{
+			puzzleError("clue.rows[1][1].color", "goal-clue-match", errors.ErrGoalDoesNotMatchTheClue),
+		}
+
+		actual := v.Validate(puzzleSet)
+
+		assert.Error(t, actual)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("ErrorAttributedToPuzzle", func(t *testing.T) {
+		t.Parallel()
+
+		puzzleSet := append(newPuzzleSet(), newPuzzleSet()...)
+		puzzleSet[1].ID = "second"
+		puzzleSet[1].Title = ""
+
+		actual := v.Validate(puzzleSet)
+
+		var puzzleError *errors.PuzzleError
+
+		assert.ErrorIs(t, actual, errors.ErrEmptyTitle)
+		assert.ErrorAs(t, actual, &puzzleError)
+		assert.Equal(t, 1, puzzleError.Index)
+		assert.Equal(t, "second", puzzleError.ID)
+	})
+}
+
+func TestUniqueSolution(t *testing.T) {
+	t.Parallel()
+
+	v := validator.New(validator.UniqueSolution)
+
+	unique := func() ast.PuzzleSet {
+		puzzleSet := newPuzzleSet()
+		puzzleSet[0].Goal = &ast.Goal{
+			{ast.Char('x'), ast.Char('x'), ast.Char('.')},
+			{ast.Char('x'), ast.Char('.'), ast.Char('.')},
+			{ast.Char('.'), ast.Char('.'), ast.Char('.')},
+		}
+		puzzleSet[0].Clue = tools.GoalToClue(*puzzleSet[0].Goal, ast.Char('.'))
+
+		return puzzleSet
+	}
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		err := v.Validate(unique())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("OkWithoutGoal", func(t *testing.T) {
+		t.Parallel()
+
+		puzzleSet := unique()
+		puzzleSet[0].Goal = nil
+
+		err := v.Validate(puzzleSet)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("OkDefaultValidator", func(t *testing.T) {
+		t.Parallel()
+
+		err := validator.New().Validate(newPuzzleSet())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("ErrorCauseMultipleSolutions", func(t *testing.T) {
+		t.Parallel()
+
+		expected := errors.ValidationError{
+			puzzleError("clue", "unique-solution", &validator.MultipleSolutionsError{
+				Solution: ast.Goal{
+					{ast.Char('.'), ast.Char('x'), ast.Char('x')},
+					{ast.Char('x'), ast.Char('.'), ast.Char('x')},
+					{ast.Char('x'), ast.Char('x'), ast.Char('.')},
+				},
+			}),
+		}
+
+		actual := v.Validate(newPuzzleSet())
+
+		assert.ErrorIs(t, actual, errors.ErrMultipleSolutions)
+		assert.Equal(t, expected, actual)
+		assert.EqualError(t, actual.(errors.ValidationError)[0].(*errors.PuzzleError).Err,
+			`clue has multiple solutions, e.g. [".xx", "x.x", "xx."]`)
+	})
+
+	t.Run("ErrorCauseMultipleSolutionsWithoutGoal", func(t *testing.T) {
+		t.Parallel()
+
+		puzzleSet := newPuzzleSet()
+		puzzleSet[0].Goal = nil
+
+		var multipleSolutionsError *validator.MultipleSolutionsError
+
+		actual := v.Validate(puzzleSet)
+
+		assert.ErrorAs(t, actual, &multipleSolutionsError)
+		assert.Equal(t, ast.Goal{
+			{ast.Char('x'), ast.Char('x'), ast.Char('.')},
+			{ast.Char('x'), ast.Char('.'), ast.Char('x')},
+			{ast.Char('.'), ast.Char('x'), ast.Char('x')},
+		}, multipleSolutionsError.Solution)
+	})
+
+	t.Run("ErrorCauseUnsolvable", func(t *testing.T) {
+		t.Parallel()
+
+		puzzleSet := newPuzzleSet()
+		puzzleSet[0].Goal = nil
+		puzzleSet[0].Clue.Rows[2] = ast.Line{{Color: ast.Char('x'), Count: 1}}
+
+		expected := errors.ValidationError{
+			puzzleError("clue.rows[0]", "unique-solution", errors.ErrUnsolvable),
+		}
+
+		actual := v.Validate(puzzleSet)
+
+		assert.Equal(t, expected, actual)
+	})
+}
+
+func TestWithContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, option := range [...]struct {
+		name   string
+		option validator.Option
+	}{
+		{name: "UniqueSolution", option: validator.UniqueSolution},
+		{name: "Solvable", option: validator.Solvable},
+		{name: "FillGoal", option: validator.FillGoal},
+	} {
+		option := option
+
+		t.Run("ErrorCauseCanceled"+option.name, func(t *testing.T) {
+			t.Parallel()
+
+			puzzleSet := newPuzzleSet()
+			puzzleSet[0].Goal = nil
+
+			var puzzleError *errors.PuzzleError
+
+			actual := validator.New(option.option, validator.WithContext(ctx)).Validate(puzzleSet)
+
+			assert.ErrorIs(t, actual, context.Canceled)
+			assert.ErrorAs(t, actual, &puzzleError)
+			assert.Equal(t, "clue", puzzleError.Field)
+		})
+	}
+}
+
+func TestSolvable(t *testing.T) {
+	t.Parallel()
+
+	unsolvable := func() ast.PuzzleSet {
+		puzzleSet := newPuzzleSet()
+		puzzleSet[0].Goal = nil
+		puzzleSet[0].Clue.Rows[2] = ast.Line{{Color: ast.Char('x'), Count: 4}}
+
+		return puzzleSet
+	}
+
+	t.Run("OkWithGoal", func(t *testing.T) {
+		t.Parallel()
+
+		err := validator.New(validator.Solvable).Validate(newPuzzleSet())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("OkWithoutGoal", func(t *testing.T) {
+		t.Parallel()
+
+		puzzleSet := newPuzzleSet()
+		puzzleSet[0].Goal = nil
+
+		err := validator.New(validator.Solvable).Validate(puzzleSet)
+
+		assert.NoError(t, err)
+		assert.Nil(t, puzzleSet[0].Goal)
+	})
+
+	t.Run("OkFillGoal", func(t *testing.T) {
+		t.Parallel()
+
+		expected := &ast.Goal{
+			{ast.Char('x'), ast.Char('x'), ast.Char('.')},
+			{ast.Char('x'), ast.Char('.'), ast.Char('.')},
+			{ast.Char('.'), ast.Char('.'), ast.Char('.')},
+		}
+
+		puzzleSet := newPuzzleSet()
+		puzzleSet[0].Clue = tools.GoalToClue(*expected, ast.Char('.'))
+		puzzleSet[0].Goal = nil
+
+		err := validator.New(validator.FillGoal).Validate(puzzleSet)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, puzzleSet[0].Goal)
+	})
+
+	t.Run("OkFillGoalMultipleSolutions", func(t *testing.T) {
+		t.Parallel()
+
+		puzzleSet := newPuzzleSet()
+		puzzleSet[0].Goal = nil
+
+		err := validator.New(validator.FillGoal).Validate(puzzleSet)
+
+		assert.NoError(t, err)
+		assert.Nil(t, puzzleSet[0].Goal)
+	})
+
+	for _, option := range [...]struct {
+		name   string
+		option validator.Option
+	}{
+		{name: "Solvable", option: validator.Solvable},
+		{name: "FillGoal", option: validator.FillGoal},
+	} {
+		option := option
+
+		t.Run("ErrorCauseUnsolvable"+option.name, func(t *testing.T) {
+			t.Parallel()
+
+			puzzleSet := unsolvable()
+
+			expected := errors.ValidationError{
+				puzzleError("clue.rows[2]", "solvable", errors.ErrUnsolvable),
+			}
+
+			actual := validator.New(option.option).Validate(puzzleSet)
+
+			assert.Equal(t, expected, actual)
+			assert.Nil(t, puzzleSet[0].Goal)
+		})
+	}
+}
+
+func puzzleError(field, rule string, err error) *errors.PuzzleError {
+	return &errors.PuzzleError{
+		ID:    "id",
+		Field: field,
+		Rule:  rule,
+		Err:   err,
+	}
 }
 
 func newPuzzleSet() ast.PuzzleSet {
@@ -0,0 +1,205 @@
+package hanjie
+
+import (
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/alexeyco/hanjie/ast"
+	"github.com/alexeyco/hanjie/errors"
+)
+
+// CurrentVersion is the version of the document format the ast types describe.
+// Version 1 documents are bare lists of puzzles, since version 2 documents are mappings with
+// the version and the list of puzzles, e.g. {version: 2, puzzles: [...]}.
+const CurrentVersion = 2
+
+const (
+	versionKey = "version"
+	puzzlesKey = "puzzles"
+)
+
+// migration converts a document to the next version and back. Documents of all versions are passed in
+// the mapping form, so version 1 documents are wrapped on reading and unwrapped on writing.
+type migration struct {
+	up   func(document *yaml.Node) error
+	down func(document *yaml.Node) error
+}
+
+// migrations by the version they upgrade from.
+var migrations = map[int]migration{
+	1: {up: upgradeV1, down: downgradeV2},
+}
+
+// upgrade migrates the document to CurrentVersion and returns the list of puzzles.
+// The nodes of puzzles are kept, so errors can be positioned in the source document.
+func upgrade(o Options, root *yaml.Node) (*yaml.Node, error) {
+	node := resolve(root)
+
+	var document *yaml.Node
+
+	switch node.Kind {
+	case yaml.SequenceNode:
+		document = envelope(1, node)
+	case yaml.MappingNode:
+		document = node
+	default:
+		return nil, positionedError(o, node, fmt.Errorf("%w: document should be a list of puzzles or a mapping with %s and %s",
+			errors.ErrSyntax, versionKey, puzzlesKey))
+	}
+
+	version, err := documentVersion(o, document)
+	if err != nil {
+		return nil, err
+	}
+
+	for ; version < CurrentVersion; version++ {
+		if err = migrations[version].up(document); err != nil {
+			return nil, positionedError(o, node, err)
+		}
+	}
+
+	setVersion(document, CurrentVersion)
+
+	puzzles := child(document, puzzlesKey)
+	if puzzles == nil {
+		return nil, positionedError(o, document, fmt.Errorf("%w: document should have %s", errors.ErrSyntax, puzzlesKey))
+	}
+
+	return puzzles, nil
+}
+
+// downgrade returns the document of the version holding the puzzles.
+func downgrade(puzzles *yaml.Node, version int) (*yaml.Node, error) {
+	if err := checkVersion(version); err != nil {
+		return nil, err
+	}
+
+	document := envelope(CurrentVersion, puzzles)
+
+	for v := CurrentVersion; v > version; v-- {
+		if err := migrations[v-1].down(document); err != nil {
+			return nil, err
+		}
+	}
+
+	setVersion(document, version)
+
+	if version == 1 {
+		return child(document, puzzlesKey), nil
+	}
+
+	return document, nil
+}
+
+func documentVersion(o Options, document *yaml.Node) (int, error) {
+	node := child(document, versionKey)
+	if node == nil {
+		return 0, positionedError(o, document, fmt.Errorf("%w: document should have %s", errors.ErrSyntax, versionKey))
+	}
+
+	var version int
+	if err := node.Decode(&version); err != nil {
+		return 0, positionedError(o, node, fmt.Errorf(`%w: wrong %s "%s"`, errors.ErrSyntax, versionKey, node.Value))
+	}
+
+	if err := checkVersion(version); err != nil {
+		return 0, positionedError(o, node, err)
+	}
+
+	return version, nil
+}
+
+func checkVersion(version int) error {
+	if version < 1 || version > CurrentVersion {
+		return fmt.Errorf("%w %d, versions 1 to %d are supported", errors.ErrUnsupportedVersion, version, CurrentVersion)
+	}
+
+	return nil
+}
+
+func envelope(version int, puzzles *yaml.Node) *yaml.Node {
+	return &yaml.Node{
+		Kind: yaml.MappingNode,
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: versionKey},
+			{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(version)},
+			{Kind: yaml.ScalarNode, Value: puzzlesKey},
+			puzzles,
+		},
+	}
+}
+
+func setVersion(document *yaml.Node, version int) {
+	if node := child(document, versionKey); node != nil {
+		node.Value = strconv.Itoa(version)
+	}
+}
+
+func positionedError(o Options, node *yaml.Node, err error) error {
+	return &errors.SyntaxError{
+		Position: errors.Position{File: o.Filename, Line: node.Line, Column: node.Column},
+		Err:      err,
+	}
+}
+
+// upgradeV1 does nothing, puzzles of version 1 are read by version 2 as they are.
+func upgradeV1(*yaml.Node) error {
+	return nil
+}
+
+// downgradeV2 writes clue lines as lists of items and goal rows as lists of chars,
+// version 1 knows neither the compact line notation nor goal row strings.
+func downgradeV2(document *yaml.Node) error {
+	puzzles := child(document, puzzlesKey)
+	if puzzles == nil {
+		return nil
+	}
+
+	for _, puzzle := range puzzles.Content {
+		var p ast.Puzzle
+		if err := puzzle.Decode(&p); err != nil {
+			return err
+		}
+
+		clue := child(puzzle, "clue")
+		for key, lines := range map[string][]ast.Line{"columns": p.Clue.Columns, "rows": p.Clue.Rows} {
+			node := child(clue, key)
+			if node == nil {
+				continue
+			}
+
+			items := make([][]ast.Item, 0, len(lines))
+			for _, line := range lines {
+				items = append(items, line)
+			}
+
+			if err := replaceContent(node, items); err != nil {
+				return err
+			}
+		}
+
+		if node := child(puzzle, "goal"); node != nil && p.Goal != nil {
+			if err := replaceContent(node, [][]ast.Char(*p.Goal)); err != nil {
+				return err
+			}
+
+			node.Style = yaml.FlowStyle
+		}
+	}
+
+	return nil
+}
+
+// replaceContent replaces elements of the sequence node with v encoded as list of lists.
+func replaceContent(node *yaml.Node, v interface{}) error {
+	var encoded yaml.Node
+	if err := encoded.Encode(v); err != nil {
+		return err
+	}
+
+	node.Content = encoded.Content
+
+	return nil
+}